@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/commands/auth"
+)
+
+// Auth takes keyword then calls subcommand.
+func Auth(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils auth <command> [<args>...]
+
+    login            log in to Jira with OAuth and cache the token.
+    logout           remove the cached OAuth token.
+    status           show the cached OAuth token status.
+
+Options:
+	-h --help      Show this screen.
+
+Description:
+	See 'jira-utils auth <command> --help' to read about a specific subcommand.
+  `
+	parser := &docopt.Parser{
+		HelpHandler:   docopt.PrintHelpAndExit,
+		OptionsFirst:  true,
+		SkipHelpFlags: false,
+	}
+
+	opts, err := parser.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		if _, ok := err.(*docopt.UserError); ok {
+			fmt.Printf(
+				"Invalid option: 'jira-util %s'. Use flag '--help' to read about a specific subcommand.\n",
+				strings.Join(os.Args[1:], " "),
+			)
+		}
+		os.Exit(1)
+	}
+
+	command := opts["<command>"].(string)
+	arguments := append([]string{"auth", command}, opts["<args>"].([]string)...)
+
+	switch command {
+	case "login":
+		return auth.Login(ctx, arguments)
+	case "logout":
+		return auth.Logout(ctx, arguments)
+	case "status":
+		return auth.Status(ctx, arguments)
+	default:
+		fmt.Println(doc)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+const baseURL = "https://jira.example.com"
+
+// setup uses a temporary config directory and baseURL with auth oauth
+func setup(t *testing.T) {
+	t.Helper()
+	t.Setenv("JIRA_UTILS_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("JIRA_PROFILE", "")
+	t.Setenv("JIRA_BASE_URL", baseURL)
+	t.Setenv("JIRA_AUTH", "oauth")
+	if err := jira.UseProfile(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeToken caches token for baseURL, in a file with permissions perm
+func writeToken(t *testing.T, token *jira.OAuthToken, perm os.FileMode) string {
+	t.Helper()
+	path, err := jira.OAuthTokenPath(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := json.Marshal(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// captureStdout returns what run writes to stdout
+func captureStdout(t *testing.T, run func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan []byte)
+	go func() {
+		content, _ := io.ReadAll(r)
+		output <- content
+	}()
+	err = run()
+	w.Close()
+	return string(<-output), err
+}
+
+func TestLogout(t *testing.T) {
+	setup(t)
+	path := writeToken(t, &jira.OAuthToken{AccessToken: "access", APIURL: baseURL}, 0o600)
+
+	for i := 0; i < 2; i++ {
+		// logging out twice is not an error
+		if _, err := captureStdout(t, func() error {
+			return Logout(context.TODO(), []string{"auth", "logout"})
+		}); err != nil {
+			t.Fatalf("logout %d: %v", i, err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("logout %d: token file not removed (%v)", i, err)
+		}
+	}
+
+	t.Setenv("JIRA_BASE_URL", "")
+	if err := Logout(context.TODO(), []string{"auth", "logout"}); !errors.Is(err, jira.ErrMissingConfig) {
+		t.Errorf("got error %v without base URL, want ErrMissingConfig", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	expiry := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name  string
+		token *jira.OAuthToken
+		perm  os.FileMode
+		want  jira.OAuthStatus
+	}{
+		{name: "not logged in", want: jira.OAuthStatus{}},
+		{name: "logged in", token: &jira.OAuthToken{AccessToken: "access", RefreshToken: "refresh", APIURL: baseURL},
+			perm: 0o600, want: jira.OAuthStatus{LoggedIn: true, CanRefresh: true, APIURL: baseURL}},
+		{name: "expired", token: &jira.OAuthToken{AccessToken: "access", Expiry: expiry, APIURL: baseURL},
+			perm: 0o600, want: jira.OAuthStatus{LoggedIn: true, Expiry: &expiry, Expired: true, APIURL: baseURL}},
+		{name: "readable by others", token: &jira.OAuthToken{AccessToken: "access", APIURL: baseURL},
+			perm: 0o644, want: jira.OAuthStatus{Error: "permissions 0644"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup(t)
+			tokenFile, err := jira.OAuthTokenPath(baseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.token != nil {
+				writeToken(t, tt.token, tt.perm)
+			}
+
+			output, err := captureStdout(t, func() error {
+				return Status(context.TODO(), []string{"auth", "status", "--output=json"})
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got jira.OAuthStatus
+			if err := json.Unmarshal([]byte(output), &got); err != nil {
+				t.Fatalf("invalid output %q: %v", output, err)
+			}
+
+			if got.BaseURL != baseURL || got.Auth != "oauth" || got.TokenFile != tokenFile {
+				t.Errorf("got base URL %s, auth %s and token file %s", got.BaseURL, got.Auth, got.TokenFile)
+			}
+			if got.LoggedIn != tt.want.LoggedIn || got.Expired != tt.want.Expired ||
+				got.CanRefresh != tt.want.CanRefresh || got.APIURL != tt.want.APIURL {
+				t.Errorf("got status %+v, want %+v", got, tt.want)
+			}
+			if (got.Expiry == nil) != (tt.want.Expiry == nil) || (got.Expiry != nil && !got.Expiry.Equal(*tt.want.Expiry)) {
+				t.Errorf("got expiry %v, want %v", got.Expiry, tt.want.Expiry)
+			}
+			if (got.Error == "") != (tt.want.Error == "") ||
+				(tt.want.Error != "" && !strings.Contains(got.Error, tt.want.Error)) {
+				t.Errorf("got error %q, want %q", got.Error, tt.want.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Login performs the OAuth flow against Jira and caches the token
+func Login(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils auth login [--redirect-url=<url>]
+Options:
+  -h --help             Show this screen.
+     --redirect-url=<url>  Callback URL registered in the Jira OAuth application [default: ` + jira.DefaultOAuthRedirectURL + `].
+
+Description:
+  The auth login command performs the OAuth 2.0 authorization code flow against Jira (Jira Cloud or
+  Jira Data Center/Server 8.22+): it prints the URL to open in a browser to authorize jira_utils,
+  waits for Jira to redirect to the callback URL, then caches access and refresh tokens in a file
+  readable by the current user only. The OAuth application client ID and secret are set with
+  env variables JIRA_OAUTH_CLIENT_ID/JIRA_OAUTH_CLIENT_SECRET or in the config profile.
+  Set auth to oauth to use the cached token, which is refreshed automatically when expired.
+
+  With auth oauth1, the command performs the OAuth 1.0a flow of Jira Data Center/Server application
+  links instead: the consumer key is set as client ID and the path of the private key, whose public
+  key is set in the application link, with env variable JIRA_OAUTH_PRIVATE_KEY or in the config profile.
+  OAuth 1.0a access tokens cannot be refreshed: when one expires, log in again.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	logger := klogr.New()
+
+	authName, _ := jira.LookupSetting(jira.AuthSetting)
+	authType, err := jira.ParseAuthType(authName)
+	if err != nil {
+		return err
+	}
+
+	redirectURL := parsedArgs["--redirect-url"].(string)
+	if authType == jira.AuthOAuth1 {
+		err = loginOAuth1(ctx, redirectURL, logger)
+	} else {
+		err = loginOAuth2(ctx, redirectURL, logger)
+	}
+	if err != nil {
+		return err
+	}
+
+	baseURL, _ := jira.LookupSetting(jira.BaseURLSetting)
+	fmt.Printf("Logged in to %s\n", baseURL)
+	if authType != jira.AuthOAuth && authType != jira.AuthOAuth1 {
+		fmt.Printf("Set auth to %s (env variable JIRA_AUTH or auth in config profile) to use the OAuth token\n", jira.AuthOAuth)
+	}
+	return nil
+}
+
+// loginOAuth2 performs the OAuth 2.0 authorization code flow
+func loginOAuth2(ctx context.Context, redirectURL string, logger logr.Logger) error {
+	if err := jira.VerifySettings(jira.BaseURLSetting, jira.OAuthClientIDSetting, jira.OAuthClientSecretSetting); err != nil {
+		return err
+	}
+
+	baseURL, _ := jira.LookupSetting(jira.BaseURLSetting)
+	clientID, _ := jira.LookupSetting(jira.OAuthClientIDSetting)
+	clientSecret, err := jira.LookupSecret(jira.OAuthClientSecretSetting)
+	if err != nil {
+		return err
+	}
+
+	_, err = jira.OAuthLogin(ctx, baseURL, clientID, clientSecret, redirectURL, os.Stdout, logger)
+	return err
+}
+
+// loginOAuth1 performs the OAuth 1.0a flow of application links
+func loginOAuth1(ctx context.Context, redirectURL string, logger logr.Logger) error {
+	if err := jira.VerifySettings(jira.BaseURLSetting, jira.OAuthClientIDSetting, jira.OAuthPrivateKeySetting); err != nil {
+		return err
+	}
+
+	baseURL, _ := jira.LookupSetting(jira.BaseURLSetting)
+	consumerKey, _ := jira.LookupSetting(jira.OAuthClientIDSetting)
+	privateKeyPath, _ := jira.LookupSetting(jira.OAuthPrivateKeySetting)
+	privateKey, err := jira.LoadOAuthPrivateKey(privateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = jira.OAuth1Login(ctx, baseURL, consumerKey, privateKey, redirectURL, os.Stdout, logger)
+	return err
+}
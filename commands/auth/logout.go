@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Logout removes the cached OAuth token
+func Logout(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils auth logout
+Options:
+  -h --help             Show this screen.
+
+Description:
+  The auth logout command removes the OAuth token cached by 'jira-utils auth login' for the Jira base URL in use.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if err := jira.VerifySettings(jira.BaseURLSetting); err != nil {
+		return err
+	}
+
+	baseURL, _ := jira.LookupSetting(jira.BaseURLSetting)
+	if err := jira.DeleteOAuthToken(baseURL); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged out of %s\n", baseURL)
+	return nil
+}
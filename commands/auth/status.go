@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Status displays the status of the cached OAuth token
+func Status(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils auth status [--output=<format>]
+Options:
+  -h --help          Show this screen.
+     --output=<format>  Output format: table, json or yaml [default: table].
+
+Description:
+  The auth status command shows whether an OAuth token is cached for the Jira base URL in use,
+  when it expires and whether it can be refreshed.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	output, err := jira.ParseOutputFormat(parsedArgs["--output"].(string))
+	if err != nil {
+		return err
+	}
+
+	return jira.DisplayOAuthStatus(os.Stdout, output)
+}
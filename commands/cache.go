@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/commands/cache"
+)
+
+// Cache takes keyword then calls subcommand.
+func Cache(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils cache <command> [<args>...]
+
+    clear            remove cached projects, boards and sprints.
+
+Options:
+	-h --help      Show this screen.
+
+Description:
+	See 'jira-utils cache <command> --help' to read about a specific subcommand.
+  `
+	parser := &docopt.Parser{
+		HelpHandler:   docopt.PrintHelpAndExit,
+		OptionsFirst:  true,
+		SkipHelpFlags: false,
+	}
+
+	opts, err := parser.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		if _, ok := err.(*docopt.UserError); ok {
+			fmt.Printf(
+				"Invalid option: 'jira-util %s'. Use flag '--help' to read about a specific subcommand.\n",
+				strings.Join(os.Args[1:], " "),
+			)
+		}
+		os.Exit(1)
+	}
+
+	command := opts["<command>"].(string)
+	arguments := append([]string{"cache", command}, opts["<args>"].([]string)...)
+
+	switch command {
+	case "clear":
+		return cache.Clear(ctx, arguments)
+	default:
+		fmt.Println(doc)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Clear removes cached Jira responses
+func Clear(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils cache clear [--all]
+Options:
+  -h --help             Show this screen.
+     --all              Clear the cache of all base URLs and profiles.
+
+Description:
+  The cache clear command removes the projects, boards and sprints cached for the Jira base URL and profile in use.
+  Next commands fetch them again from Jira. Use the global option --no-cache to ignore the cache for one command.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if all := parsedArgs["--all"].(bool); all {
+		if err := jira.ClearCaches(); err != nil {
+			return err
+		}
+		fmt.Println("Cleared cache of all base URLs and profiles")
+		return nil
+	}
+
+	if err := jira.VerifySettings(jira.BaseURLSetting); err != nil {
+		return err
+	}
+
+	baseURL, _ := jira.LookupSetting(jira.BaseURLSetting)
+	cache, err := jira.GetResponseCache(baseURL)
+	if err != nil {
+		return err
+	}
+	if err := cache.Clear(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Cleared cache of %s\n", baseURL)
+	return nil
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/commands/config"
+)
+
+// Config takes keyword then calls subcommand.
+func Config(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils config <command> [<args>...]
+
+    show             show settings in use and where each comes from.
+    validate         verify settings are complete and valid.
+
+Options:
+	-h --help      Show this screen.
+
+Description:
+	See 'jira-utils config <command> --help' to read about a specific subcommand.
+  `
+	parser := &docopt.Parser{
+		HelpHandler:   docopt.PrintHelpAndExit,
+		OptionsFirst:  true,
+		SkipHelpFlags: false,
+	}
+
+	opts, err := parser.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		if _, ok := err.(*docopt.UserError); ok {
+			fmt.Printf(
+				"Invalid option: 'jira-util %s'. Use flag '--help' to read about a specific subcommand.\n",
+				strings.Join(os.Args[1:], " "),
+			)
+		}
+		os.Exit(1)
+	}
+
+	command := opts["<command>"].(string)
+	arguments := append([]string{"config", command}, opts["<args>"].([]string)...)
+
+	switch command {
+	case "show":
+		return config.Show(ctx, arguments)
+	case "validate":
+		return config.Validate(ctx, arguments)
+	default:
+		fmt.Println(doc)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Show displays the settings in use and their source
+func Show(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils config show [--output=<format>]
+Options:
+  -h --help          Show this screen.
+     --output=<format>  Output format: table, json or yaml [default: table].
+
+Description:
+  The config show command shows the config file and profile in use and, for each setting,
+  its value and where it comes from (env variable or config profile). Password is masked.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	output, err := jira.ParseOutputFormat(parsedArgs["--output"].(string))
+	if err != nil {
+		return err
+	}
+
+	return jira.DisplaySettings(os.Stdout, output)
+}
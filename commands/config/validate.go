@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Validate verifies all settings are set and valid
+func Validate(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils config validate [--connect]
+Options:
+  -h --help          Show this screen.
+     --connect       Also connect to Jira and verify project and board exist.
+
+Description:
+  The config validate command verifies all settings (base URL, project, board and the credentials needed
+  by the auth type in use) are set, either with env variables or in the config profile, and well formed.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if err := jira.ValidateSettings(); err != nil {
+		return err
+	}
+
+	if parsedArgs["--connect"].(bool) {
+		logger := klogr.New()
+		// cached responses would not prove Jira can be reached
+		jira.DisableCache()
+
+		credentials, err := jira.GetCredentials(logger)
+		if err != nil {
+			return err
+		}
+
+		jiraClient, err := jira.GetJiraClient(ctx, credentials, logger)
+		if err != nil {
+			return err
+		}
+
+		project, err := jira.GetJiraProject(ctx, jiraClient, "", logger)
+		if err != nil {
+			return err
+		}
+
+		if _, err := jira.GetJiraBoard(ctx, jiraClient, project.Key, "", logger); err != nil {
+			return err
+		}
+	}
+
+	profile := jira.ActiveProfile()
+	if profile == "" {
+		profile = "(none)"
+	}
+	fmt.Printf("Configuration is valid (profile: %s)\n", profile)
+	return nil
+}
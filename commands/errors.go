@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Exit codes of jira-utils, one per failure type, so that scripts can branch on them
+const (
+	ExitOK             = 0
+	ExitError          = 1
+	ExitMissingConfig  = 2
+	ExitAuthFailed     = 3
+	ExitNotFound       = 4
+	ExitAmbiguous      = 5
+	ExitNoActiveSprint = 6
+	// ExitInterrupted is the code used by shells for commands terminated by SIGINT (128 + 2)
+	ExitInterrupted = 130
+)
+
+// exitErrors maps errors returned by package jira to exit code and a hint for the user
+var exitErrors = []struct {
+	err  error
+	code int
+	hint string
+}{
+	{jira.ErrMissingConfig, ExitMissingConfig, "Run 'jira-utils config show' to see the settings in use and where they come from."},
+	{jira.ErrNotLoggedIn, ExitAuthFailed, ""},
+	{jira.ErrAuthFailed, ExitAuthFailed, "Run 'jira-utils config validate --connect' after fixing the credentials."},
+	{jira.ErrAmbiguousBoard, ExitAmbiguous, "Pass --board with the exact name or the ID of one of the boards (see 'jira-utils show boards')."},
+	{jira.ErrAmbiguousSprint, ExitAmbiguous, "Pass --sprint with the name of one of the active sprints (see 'jira-utils show sprints --state=active')."},
+	{jira.ErrNoActiveSprint, ExitNoActiveSprint, "Pass --sprint to select a sprint."},
+	{jira.ErrNotFound, ExitNotFound, "Check project, board, sprint and issue names."},
+	{context.Canceled, ExitInterrupted, ""},
+}
+
+// ExitCode returns the exit code for err and, if any, a hint on how to fix it
+func ExitCode(err error) (code int, hint string) {
+	if err == nil {
+		return ExitOK, ""
+	}
+	for i := range exitErrors {
+		if errors.Is(err, exitErrors[i].err) {
+			return exitErrors[i].code, exitErrors[i].hint
+		}
+	}
+	return ExitError, ""
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantHint bool
+	}{
+		{"no error", nil, ExitOK, false},
+		{"generic error", errors.New("boom"), ExitError, false},
+		{"missing config", jira.ErrMissingConfig, ExitMissingConfig, true},
+		{"wrapped missing config", fmt.Errorf("setting %s: %w", jira.TokenSetting, jira.ErrMissingConfig), ExitMissingConfig, true},
+		{"not logged in", jira.ErrNotLoggedIn, ExitAuthFailed, false},
+		{"auth failed", fmt.Errorf("GET /myself: %w", jira.ErrAuthFailed), ExitAuthFailed, true},
+		{"ambiguous board", fmt.Errorf("board cloud: %w", jira.ErrAmbiguousBoard), ExitAmbiguous, true},
+		{"ambiguous sprint", jira.ErrAmbiguousSprint, ExitAmbiguous, true},
+		{"no active sprint", jira.ErrNoActiveSprint, ExitNoActiveSprint, true},
+		{"not found", fmt.Errorf("project KUBE: %w", jira.ErrNotFound), ExitNotFound, true},
+		{"interrupted", fmt.Errorf("search: %w", context.Canceled), ExitInterrupted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, hint := ExitCode(tt.err)
+			if code != tt.wantCode {
+				t.Errorf("got exit code %d, want %d", code, tt.wantCode)
+			}
+			if (hint != "") != tt.wantHint {
+				t.Errorf("got hint %q, want a hint: %t", hint, tt.wantHint)
+			}
+		})
+	}
+}
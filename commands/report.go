@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/commands/report"
+)
+
+// Report takes keyword then calls subcommand.
+func Report(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils report <command> [<args>...]
+
+    burndown         show remaining work of a sprint, day by day.
+    sprint-scope     show issues of a sprint carried over, added, removed and unresolved.
+    time-in-status   show how long issues spent in each status and with each assignee.
+    velocity         show work committed and completed in past sprints.
+
+Options:
+	-h --help      Show this screen.
+
+Description:
+	See 'jira-utils report <command> --help' to read about a specific subcommand.
+  `
+	parser := &docopt.Parser{
+		HelpHandler:   docopt.PrintHelpAndExit,
+		OptionsFirst:  true,
+		SkipHelpFlags: false,
+	}
+
+	opts, err := parser.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		if _, ok := err.(*docopt.UserError); ok {
+			fmt.Printf(
+				"Invalid option: 'jira-util %s'. Use flag '--help' to read about a specific subcommand.\n",
+				strings.Join(os.Args[1:], " "),
+			)
+		}
+		os.Exit(1)
+	}
+
+	command := opts["<command>"].(string)
+	arguments := append([]string{"report", command}, opts["<args>"].([]string)...)
+
+	switch command {
+	case "burndown":
+		return report.Burndown(ctx, arguments)
+	case "sprint-scope":
+		return report.SprintScope(ctx, arguments)
+	case "time-in-status":
+		return report.TimeInStatus(ctx, arguments)
+	case "velocity":
+		return report.Velocity(ctx, arguments)
+	default:
+		fmt.Println(doc)
+	}
+
+	return nil
+}
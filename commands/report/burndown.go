@@ -0,0 +1,96 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/commands/utils"
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Burndown displays remaining work of a sprint, day by day
+func Burndown(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils report burndown (--sprint=<name>|--active) [--project=<name>] [--board=<name>] [--unit=<unit>] [--chart=<type>] [--done-statuses=<list>] [--svg=<file>] [--output=<format>|--template=<template>|--template-file=<file>]
+Options:
+  -h --help               Show this screen.
+     --sprint=<name>      Report on the specified sprint.
+     --active             Report on the active sprint.
+     --project=<name>     Project of the sprint (value in JIRA_PROJECT or config profile will be used by default)
+     --board=<name>       Board of the sprint (value in JIRA_BOARD or config profile will be used by default)
+     --unit=<unit>        Measure work in points or issues (issues if no story points field exists) [default: points].
+     --chart=<type>       Chart remaining work (burndown) or completed work and scope (burnup) [default: burndown].
+     --done-statuses=<list>  Comma separated statuses of completed issues [default: Resolved,Closed,Done].
+     --svg=<file>         Also write the chart as a standalone SVG image to file.
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
+
+Description:
+  The report burndown command reconstructs, from the changelog of the sprint issues, the sprint scope, completed and
+  remaining work at sprint start and at the end of each day until the sprint ends (or now), accounting for issues added
+  or removed after the sprint started. Table output shows an ASCII chart followed by the daily values.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if err := utils.VerifySettings(parsedArgs, jira.ProjectSetting, jira.BoardSetting); err != nil {
+		return err
+	}
+
+	burndownOptions, err := getBurndownOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	var burnup bool
+	switch chart := parsedArgs["--chart"].(string); chart {
+	case "burndown":
+	case "burnup":
+		burnup = true
+	default:
+		return fmt.Errorf("--chart: unsupported value %q: must be burndown or burnup", chart)
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	logger := klogr.New()
+
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	boardID, projectKey, err := getBoardID(ctx, jiraClient, parsedArgs, logger)
+	if err != nil {
+		return err
+	}
+	// issues of the project updated during the sprint are searched for issues removed from it
+	burndownOptions.Project = projectKey
+
+	// the SVG file is only created once the burndown is built
+	svgPath := ""
+	if passedSVG := parsedArgs["--svg"]; passedSVG != nil {
+		svgPath = passedSVG.(string)
+	}
+
+	return jira.DisplayBurndown(ctx, jiraClient, boardID, getSprintName(parsedArgs), burnup, burndownOptions,
+		displayOptions, svgPath, logger)
+}
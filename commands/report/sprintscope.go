@@ -0,0 +1,78 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/commands/utils"
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// SprintScope displays issues of a sprint carried over, added, removed and unresolved
+func SprintScope(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils report sprint-scope (--sprint=<name>|--active) [--project=<name>] [--board=<name>] [--done-statuses=<list>] [--output=<format>|--template=<template>|--template-file=<file>]
+Options:
+  -h --help               Show this screen.
+     --sprint=<name>      Report on the specified sprint.
+     --active             Report on the active sprint.
+     --project=<name>     Project of the sprint (value in JIRA_PROJECT or config profile will be used by default)
+     --board=<name>       Board of the sprint (value in JIRA_BOARD or config profile will be used by default)
+     --done-statuses=<list>  Comma separated statuses of completed issues [default: Resolved,Closed,Done].
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
+
+Description:
+  The report sprint-scope command walks the Sprint field history of the sprint issues and lists the issues carried
+  over from previous sprints (with the number of sprints they were in), added or removed after the sprint started,
+  and not done when the sprint ended (or now, for the active sprint), followed by a breakdown per assignee.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if err := utils.VerifySettings(parsedArgs, jira.ProjectSetting, jira.BoardSetting); err != nil {
+		return err
+	}
+
+	scopeOptions, err := getBurndownOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	logger := klogr.New()
+
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	boardID, projectKey, err := getBoardID(ctx, jiraClient, parsedArgs, logger)
+	if err != nil {
+		return err
+	}
+	// issues of the project updated during the sprint are searched for issues removed from it
+	scopeOptions.Project = projectKey
+
+	return jira.DisplaySprintScope(ctx, jiraClient, boardID, getSprintName(parsedArgs), scopeOptions,
+		displayOptions, logger)
+}
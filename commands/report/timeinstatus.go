@@ -0,0 +1,94 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/commands/utils"
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// TimeInStatus displays how long issues spent in each status and with each assignee
+func TimeInStatus(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils report time-in-status (--sprint=<name>|--active|--jql=<expr>) [--project=<name>] [--board=<name>] [--by=<dimension>] [--page-size=<n>] [--max-results=<n>] [--output=<format>|--template=<template>|--template-file=<file>]
+Options:
+  -h --help               Show this screen.
+     --sprint=<name>      Report on all issues of the specified sprint.
+     --active             Report on all issues of the active sprints (all of them on boards with parallel sprints).
+     --jql=<expr>         Report on issues matching the JQL query (no project, board or user filter is added).
+     --project=<name>     Project of the sprint (value in JIRA_PROJECT or config profile will be used by default)
+     --board=<name>       Board of the sprint (value in JIRA_BOARD or config profile will be used by default)
+     --by=<dimension>     Show time per status or per assignee (json and yaml output always contain both) [default: status].
+     --page-size=<n>      Number of issues fetched per request to Jira (50 by default).
+     --max-results=<n>    Report on at most this number of issues (all matching issues by default).
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
+
+Description:
+  The report time-in-status command walks the changelog of each issue and shows how long, in days, it spent in every
+  status (or with every assignee) from its creation until now, followed by mean, median and 90th percentile per status.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	passedJQL := parsedArgs["--jql"]
+	var settings []string
+	if passedJQL == nil {
+		settings = []string{jira.ProjectSetting, jira.BoardSetting}
+	}
+	if err := utils.VerifySettings(parsedArgs, settings...); err != nil {
+		return err
+	}
+
+	by := parsedArgs["--by"].(string)
+	if err := jira.ValidateTimeInStatusBy(by); err != nil {
+		return fmt.Errorf("--by: %w", err)
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	logger := klogr.New()
+
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	var jql string
+	if passedJQL != nil {
+		jql = strings.TrimSpace(passedJQL.(string))
+		if jql == "" {
+			return fmt.Errorf("--jql must not be empty")
+		}
+	} else {
+		boardID, _, err := getBoardID(ctx, jiraClient, parsedArgs, logger)
+		if err != nil {
+			return err
+		}
+		jql, err = sprintJQL(ctx, jiraClient, boardID, parsedArgs, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	return jira.DisplayTimeInStatus(ctx, jiraClient, jql, by, displayOptions, logger)
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"context"
+	"fmt"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/go-logr/logr"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// getBurndownOptions returns the options set with --unit and --done-statuses
+func getBurndownOptions(parsedArgs docopt.Opts) (*jira.BurndownOptions, error) {
+	options := &jira.BurndownOptions{}
+
+	var err error
+	if passedUnit := parsedArgs["--unit"]; passedUnit != nil {
+		options.Unit, err = jira.ParseBurndownUnit(passedUnit.(string))
+		if err != nil {
+			return nil, fmt.Errorf("--unit: %w", err)
+		}
+	}
+
+	if passedDoneStatuses := parsedArgs["--done-statuses"]; passedDoneStatuses != nil {
+		options.DoneStatuses = jira.ParseDoneStatuses(passedDoneStatuses.(string))
+	}
+
+	return options, nil
+}
+
+// getBoardID returns the ID of the board selected with --project and --board
+// (settings project and board by default) and the key of the project
+func getBoardID(ctx context.Context, jiraClient jira.JiraAPI, parsedArgs docopt.Opts,
+	logger logr.Logger) (boardID, projectKey string, err error) {
+	projectName := ""
+	if passedProject := parsedArgs["--project"]; passedProject != nil {
+		projectName = passedProject.(string)
+	}
+
+	project, err := jira.GetJiraProject(ctx, jiraClient, projectName, logger)
+	if err != nil {
+		return "", "", err
+	}
+
+	boardName := ""
+	if passedBoard := parsedArgs["--board"]; passedBoard != nil {
+		boardName = passedBoard.(string)
+	}
+
+	board, err := jira.GetJiraBoard(ctx, jiraClient, project.Key, boardName, logger)
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("%d", board.ID), project.Key, nil
+}
+
+// getSprintName returns the sprint passed with --sprint, empty for the active sprint
+func getSprintName(parsedArgs docopt.Opts) string {
+	if passedSprint := parsedArgs["--sprint"]; passedSprint != nil {
+		return passedSprint.(string)
+	}
+	return ""
+}
+
+// sprintJQL returns the JQL query matching all issues of the sprint of board boardID
+// passed with --sprint, of all active sprints otherwise
+func sprintJQL(ctx context.Context, jiraClient jira.JiraAPI, boardID string, parsedArgs docopt.Opts,
+	logger logr.Logger) (string, error) {
+	if sprintName := getSprintName(parsedArgs); sprintName != "" {
+		sprint, err := jira.GetJiraSprint(ctx, jiraClient, boardID, sprintName, logger)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("sprint = %d", sprint.ID), nil
+	}
+
+	sprints, err := jira.GetJiraActiveSprints(ctx, jiraClient, boardID, logger)
+	if err != nil {
+		return "", err
+	}
+	return jira.SprintsJQL(sprints), nil
+}
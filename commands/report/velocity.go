@@ -0,0 +1,84 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/commands/utils"
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Velocity displays work committed and completed in past sprints
+func Velocity(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils report velocity [--last=<n>] [--project=<name>] [--board=<name>] [--unit=<unit>] [--done-statuses=<list>] [--output=<format>|--template=<template>|--template-file=<file>]
+Options:
+  -h --help               Show this screen.
+     --last=<n>           Report on the last n closed sprints [default: 6].
+     --project=<name>     Project of the sprints (value in JIRA_PROJECT or config profile will be used by default)
+     --board=<name>       Board of the sprints (value in JIRA_BOARD or config profile will be used by default)
+     --unit=<unit>        Measure work in points or issues (issues if no story points field exists) [default: points].
+     --done-statuses=<list>  Comma separated statuses of completed issues [default: Resolved,Closed,Done].
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
+
+Description:
+  The report velocity command shows, for each of the last closed sprints of the board, the work committed at sprint
+  start and completed by sprint end (story points and issues), reconstructed from the changelog of the sprint issues,
+  with the rolling average of the last 3 sprints, the average and the standard deviation of completed work.
+  Table output shows an ASCII bar chart followed by a table.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if err := utils.VerifySettings(parsedArgs, jira.ProjectSetting, jira.BoardSetting); err != nil {
+		return err
+	}
+
+	last, err := strconv.Atoi(parsedArgs["--last"].(string))
+	if err != nil || last <= 0 {
+		return fmt.Errorf("--last must be a number > 0")
+	}
+
+	burndownOptions, err := getBurndownOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	logger := klogr.New()
+
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	boardID, projectKey, err := getBoardID(ctx, jiraClient, parsedArgs, logger)
+	if err != nil {
+		return err
+	}
+	// issues of the project updated during the sprint are searched for issues removed from it
+	burndownOptions.Project = projectKey
+
+	return jira.DisplayVelocity(ctx, jiraClient, boardID, last, burndownOptions, displayOptions, logger)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/commands/secret"
+)
+
+// Secret takes keyword then calls subcommand.
+func Secret(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils secret <command> [<args>...]
+
+    set              add or change a secret in the secret store.
+    unset            remove a secret from the secret store.
+    list             list the secrets in the secret store.
+
+Options:
+	-h --help      Show this screen.
+
+Description:
+	See 'jira-utils secret <command> --help' to read about a specific subcommand.
+  `
+	parser := &docopt.Parser{
+		HelpHandler:   docopt.PrintHelpAndExit,
+		OptionsFirst:  true,
+		SkipHelpFlags: false,
+	}
+
+	opts, err := parser.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		if _, ok := err.(*docopt.UserError); ok {
+			fmt.Printf(
+				"Invalid option: 'jira-util %s'. Use flag '--help' to read about a specific subcommand.\n",
+				strings.Join(os.Args[1:], " "),
+			)
+		}
+		os.Exit(1)
+	}
+
+	command := opts["<command>"].(string)
+	arguments := append([]string{"secret", command}, opts["<args>"].([]string)...)
+
+	switch command {
+	case "set":
+		return secret.Set(ctx, arguments)
+	case "unset":
+		return secret.Unset(ctx, arguments)
+	case "list":
+		return secret.List(ctx, arguments)
+	default:
+		fmt.Println(doc)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+)
+
+// List displays the names of the secrets in the file or vault secret store
+func List(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils secret list [--store=<store>]
+Options:
+  -h --help          Show this screen.
+     --store=<store>  Secret store: file[:<path>] or vault[:<path>] (value in JIRA_SECRET_STORE or config profile will be used by default).
+
+Description:
+  The secret list command lists the names of the secrets in the secret store. Values are never displayed.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	store, err := getSecretStore(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	names, err := store.Names()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Secret store: %s\n", store)
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
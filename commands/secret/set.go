@@ -0,0 +1,80 @@
+package secret
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Set adds or changes a secret in the file or vault secret store
+func Set(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils secret set <name> [--store=<store>]
+Options:
+  -h --help          Show this screen.
+     --store=<store>  Secret store: file[:<path>] or vault[:<path>] (value in JIRA_SECRET_STORE or config profile will be used by default).
+
+Description:
+  The secret set command stores secret <name> (password, token or oauthClientSecret) in the secret store.
+  The value is asked on the terminal, without echoing it, or read from stdin when it is not a terminal
+  (e.g. pass show jira | jira-utils secret set password). Unlike JIRA_PASSWORD, the password is not base64 encoded.
+  The vault passphrase is asked on the terminal (twice when the vault is created) or read from env variable JIRA_VAULT_PASSPHRASE.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	name := parsedArgs["<name>"].(string)
+	if err := verifySecretName(name); err != nil {
+		return err
+	}
+
+	store, err := getSecretStore(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	value, err := readValue(name)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("value of %s cannot be empty", name)
+	}
+
+	if err := store.Set(name, value); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s saved in secret store %s\n", name, store)
+	return nil
+}
+
+// readValue returns the value of secret name: asked on the terminal if stdin
+// is a terminal, the first line of stdin otherwise
+func readValue(name string) (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		return jira.ReadSecret(fmt.Sprintf("Value of %s: ", name))
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read value of %s from stdin: %w", name, err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,51 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+)
+
+// Unset removes a secret from the file or vault secret store
+func Unset(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils secret unset <name> [--store=<store>]
+Options:
+  -h --help          Show this screen.
+     --store=<store>  Secret store: file[:<path>] or vault[:<path>] (value in JIRA_SECRET_STORE or config profile will be used by default).
+
+Description:
+  The secret unset command removes secret <name> (password, token or oauthClientSecret) from the secret store.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	name := parsedArgs["<name>"].(string)
+	if err := verifySecretName(name); err != nil {
+		return err
+	}
+
+	store, err := getSecretStore(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Remove(name); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s removed from secret store %s\n", name, store)
+	return nil
+}
@@ -0,0 +1,34 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// getSecretStore returns the secret store passed with --store or, if not
+// passed, the one in use (setting secretStore)
+func getSecretStore(parsedArgs map[string]interface{}) (*jira.SecretStore, error) {
+	if passedStore := parsedArgs["--store"]; passedStore != nil {
+		return jira.ParseSecretStore(passedStore.(string))
+	}
+
+	store, err := jira.ActiveSecretStore()
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf("no secret store in use: pass --store or set env variable JIRA_SECRET_STORE " +
+			"or secretStore in config profile")
+	}
+	return store, nil
+}
+
+// verifySecretName returns an error if name is not a secret setting
+func verifySecretName(name string) error {
+	if !jira.IsSecretSetting(name) {
+		return fmt.Errorf("%q is not a secret (secrets: %s, %s, %s)", name,
+			jira.PasswordSetting, jira.TokenSetting, jira.OAuthClientSecretSetting)
+	}
+	return nil
+}
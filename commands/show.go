@@ -19,7 +19,10 @@ func Show(ctx context.Context, args []string) error {
     issues           show jira issues.
     filed            show jira issues filed by user.
     sprints          show all sprints.
+    boards           show boards of a project.
     e2e              show all open issues filed for e2e.
+    query            show jira issues matching a JQL query.
+    saved            show jira issues matching a saved query.
 
 Options:
 	-h --help      Show this screen.
@@ -33,7 +36,7 @@ Description:
 		SkipHelpFlags: false,
 	}
 
-	opts, err := parser.ParseArgs(doc, nil, "1.0")
+	opts, err := parser.ParseArgs(doc, args, "1.0")
 	if err != nil {
 		if _, ok := err.(*docopt.UserError); ok {
 			fmt.Printf(
@@ -54,8 +57,14 @@ Description:
 		return show.Filed(ctx, arguments)
 	case "sprints":
 		return show.Sprints(ctx, arguments)
+	case "boards":
+		return show.Boards(ctx, arguments)
 	case "e2e":
 		return show.E2EIssues(ctx, arguments)
+	case "query":
+		return show.Query(ctx, arguments)
+	case "saved":
+		return show.Saved(ctx, arguments)
 	default:
 		fmt.Println(doc)
 	}
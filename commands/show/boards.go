@@ -0,0 +1,90 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/commands/utils"
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Boards displays the boards of a project
+func Boards(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils show boards [--project=<name>] [--name=<name>] [--exact] [--type=<type>] [--output=<format>|--template=<template>|--template-file=<file>]
+Options:
+  -h --help          Show this screen.
+     --project=<name>  Show boards of this project (value in JIRA_PROJECT or config profile will be used by default)
+     --name=<name>     Show only boards whose name contains name, ignoring case.
+     --exact           Show only boards whose name is name, ignoring case.
+     --type=<type>     Show only boards of this type: scrum or kanban.
+     --output=<format>  Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
+
+Description:
+  The show boards command shows ID, name and type of the boards of a project.
+  Any of them can be selected with --board, by exact name or ID, in other commands.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if err := utils.VerifySettings(parsedArgs, jira.ProjectSetting); err != nil {
+		return err
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	filter := &jira.BoardFilter{Match: jira.BoardMatchSubstring}
+	if passedName := parsedArgs["--name"]; passedName != nil {
+		filter.Name = passedName.(string)
+	}
+	if parsedArgs["--exact"].(bool) {
+		if filter.Name == "" {
+			return fmt.Errorf("--exact requires --name")
+		}
+		filter.Match = jira.BoardMatchExact
+	}
+	if passedType := parsedArgs["--type"]; passedType != nil {
+		filter.Type = strings.ToLower(passedType.(string))
+		if filter.Type != "scrum" && filter.Type != "kanban" {
+			return fmt.Errorf("unsupported board type %q (supported: scrum, kanban)", passedType)
+		}
+	}
+
+	logger := klogr.New()
+
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	projectName := ""
+	if passedProject := parsedArgs["--project"]; passedProject != nil {
+		projectName = passedProject.(string)
+	}
+
+	project, err := jira.GetJiraProject(ctx, jiraClient, projectName, logger)
+	if err != nil {
+		return err
+	}
+
+	return jira.DisplayJiraBoards(ctx, jiraClient, project.Key, filter, displayOptions, logger)
+}
@@ -3,27 +3,44 @@ package show
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 
 	docopt "github.com/docopt/docopt-go"
 	"k8s.io/klog/v2/klogr"
 
+	"github.com/gianlucam76/jira_utils/commands/utils"
 	"github.com/gianlucam76/jira_utils/jira"
 )
 
+const (
+	// e2eQuery is the name of the saved query used by show e2e
+	e2eQuery = "e2e"
+)
+
 // E2EIssues displays information about issues filed for e2e automatic tagging sanities
 func E2EIssues(ctx context.Context, args []string) error {
 	doc := `Usage:
-	jira-utils show e2e [--warn-after=<days>]
+	jira-utils show e2e [--warn-after=<list>] [--page-size=<n>] [--max-results=<n>] [--columns=<list>] [--sort-by=<list>] [--group-by=<field>] [--output=<format>|--template=<template>|--template-file=<file>]
 Options:
   -h --help               Show this screen.
-     --warn-after=<days>  Highlights any issue ii progressing status for more than number of days specified.
+     --warn-after=<list>  Highlights issues in a status for too long: days In Progress (e.g. 3) or list of <status>=<days> (e.g. "In Review=2,Blocked=5").
+     --page-size=<n>      Number of issues fetched per request to Jira (50 by default).
+     --max-results=<n>    Show at most this number of issues (all matching issues by default).
+     --columns=<list>     Comma separated list of columns to display (key,summary,status,updated,assignee by default).
+                          Any of key, summary, status, updated, assignee, reporter, priority, type, components, labels,
+                          fixVersions, created, dueDate, storyPoints, epicLink, sprint or a custom field ID or name.
+     --sort-by=<list>     Comma separated list of columns to sort issues by, each optionally followed by :asc or :desc
+                          (e.g. priority,updated:desc). Any column accepted by --columns can be used.
+     --group-by=<field>   Group issues by status, assignee, priority, component or epic, with per group counts and subtotals.
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
 
 Description:
-  The show e2e command shows information about jira issues filed for e2e automatic tagging sanities
+  The show e2e command shows information about jira issues filed for e2e automatic tagging sanities.
+  It runs the saved query e2e (see 'jira-utils show saved --list'), which can be redefined in the config file.
 `
-	parsedArgs, err := docopt.ParseArgs(doc, nil, "1.0")
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
 	if err != nil {
 		fmt.Println(err)
 		return fmt.Errorf(
@@ -36,38 +53,35 @@ Description:
 		return nil
 	}
 
-	logger := klogr.New()
-
-	username := "atom-ci.gen"
+	if err := utils.VerifySettings(parsedArgs, jira.ProjectSetting); err != nil {
+		return err
+	}
 
-	jiraClient, err := jira.GetJiraClient(ctx, jira.GetUsername(logger), jira.GetPassword(logger), logger)
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
 	if err != nil {
 		return err
 	}
 
-	projectName := ""
-	project, err := jira.GetJiraProject(ctx, jiraClient, projectName, logger)
-	if err != nil || project == nil {
-		return fmt.Errorf("failed to get jira project")
-	}
+	logger := klogr.New()
 
-	boardName := ""
-	board, err := jira.GetJiraBoard(ctx, jiraClient, project.Key, boardName, logger)
-	if err != nil || board == nil {
-		return fmt.Errorf("failed to get jira board")
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
 	}
 
-	var jql string
+	// e2e is a builtin saved query, which can be overridden in the config file
+	config := jira.ActiveConfig()
 
-	warnAfter := 0
-	if passedWarnAfter := parsedArgs["--warn-after"]; passedWarnAfter != nil {
-		warnAfter, err = strconv.Atoi(passedWarnAfter.(string))
-		if err != nil {
-			return err
-		}
+	query, err := jira.GetSavedQuery(config, e2eQuery)
+	if err != nil {
+		return err
 	}
 
-	jql = fmt.Sprintf("Status NOT IN (Resolved,Closed) and reporter = %s and project = %s", username, project.Name)
+	variables := jira.NewQueryVariables(ctx, jiraClient, "", "", "", logger)
+	jql, err := jira.ExpandQuery(e2eQuery, query, variables)
+	if err != nil {
+		return err
+	}
 
-	return jira.DisplayJiraIssues(ctx, jiraClient, jql, warnAfter, logger)
+	return jira.DisplayJiraIssues(ctx, jiraClient, jql, displayOptions, logger)
 }
@@ -3,32 +3,43 @@ package show
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 
 	docopt "github.com/docopt/docopt-go"
 	"k8s.io/klog/v2/klogr"
 
+	"github.com/gianlucam76/jira_utils/commands/utils"
 	"github.com/gianlucam76/jira_utils/jira"
 )
 
 // Filed displays information about issues filed by user (by default user defined in env variable JIRA_USERNAME)
 func Filed(ctx context.Context, args []string) error {
 	doc := `Usage:
-	jira-utils show filed [--sprint=<name>|--active] [--project=<name>] [--board=<name>] [--username=<name>] [--warn-after=<days>]
+	jira-utils show filed [--sprint=<name>|--active] [--project=<name>] [--board=<name>] [--username=<name>] [--warn-after=<list>] [--page-size=<n>] [--max-results=<n>] [--columns=<list>] [--sort-by=<list>] [--group-by=<field>] [--output=<format>|--template=<template>|--template-file=<file>]
 Options:
   -h --help             Show this screen.
-     --active           Show Jira issues in current active sprint.
+     --active           Show Jira issues in the active sprints (all of them on boards with parallel sprints).
      --username=<name>  Show Jira issues for specified user (by default user defined in env variable JIRA_USERNAME)
      --sprint=<name>    Show Jira issues in current specified sprint.
-     --project=<name>	Show Jira issues in current project (value in JIRA_PROJECT will be used by default)
-     --board=<name>     Show Jira issues in current project/board (value in JIRA_BOARD will be used by default)
-     --warn-after=<days>  Highlights any issue ii progressing status for more than number of days specified.
+     --project=<name>	Show Jira issues in current project (value in JIRA_PROJECT or config profile will be used by default)
+     --board=<name>     Show Jira issues in current project/board (value in JIRA_BOARD or config profile will be used by default)
+     --warn-after=<list>  Highlights issues in a status for too long: days In Progress (e.g. 3) or list of <status>=<days> (e.g. "In Review=2,Blocked=5").
+     --page-size=<n>      Number of issues fetched per request to Jira (50 by default).
+     --max-results=<n>    Show at most this number of issues (all matching issues by default).
+     --columns=<list>     Comma separated list of columns to display (key,summary,status,updated,assignee by default).
+                          Any of key, summary, status, updated, assignee, reporter, priority, type, components, labels,
+                          fixVersions, created, dueDate, storyPoints, epicLink, sprint or a custom field ID or name.
+     --sort-by=<list>     Comma separated list of columns to sort issues by, each optionally followed by :asc or :desc
+                          (e.g. priority,updated:desc). Any column accepted by --columns can be used.
+     --group-by=<field>   Group issues by status, assignee, priority, component or epic, with per group counts and subtotals.
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
 
 Description:
   The show filed command shows information about jira issues filed by user (by default user defined in env variable JIRA_USERNAME)
 `
-	parsedArgs, err := docopt.ParseArgs(doc, nil, "1.0")
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
 	if err != nil {
 		fmt.Println(err)
 		return fmt.Errorf(
@@ -41,16 +52,28 @@ Description:
 		return nil
 	}
 
+	if err := utils.VerifySettings(parsedArgs, jira.ProjectSetting, jira.BoardSetting, jira.UsernameSetting); err != nil {
+		return err
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
 	logger := klogr.New()
 
 	username := ""
 	if passedUsername := parsedArgs["--username"]; passedUsername != nil {
 		username = passedUsername.(string)
 	} else {
-		username = jira.GetUsername(logger)
+		username, err = jira.GetUsername(logger)
+		if err != nil {
+			return err
+		}
 	}
 
-	jiraClient, err := jira.GetJiraClient(ctx, jira.GetUsername(logger), jira.GetPassword(logger), logger)
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
 	if err != nil {
 		return err
 	}
@@ -61,8 +84,8 @@ Description:
 	}
 
 	project, err := jira.GetJiraProject(ctx, jiraClient, projectName, logger)
-	if err != nil || project == nil {
-		return fmt.Errorf("failed to get jira project")
+	if err != nil {
+		return err
 	}
 
 	boardName := ""
@@ -71,8 +94,8 @@ Description:
 	}
 
 	board, err := jira.GetJiraBoard(ctx, jiraClient, project.Key, boardName, logger)
-	if err != nil || board == nil {
-		return fmt.Errorf("failed to get jira board")
+	if err != nil {
+		return err
 	}
 
 	var jql string
@@ -82,30 +105,22 @@ Description:
 		sprintName = passedSprint.(string)
 	}
 
-	warnAfter := 0
-	if passedWarnAfter := parsedArgs["--warn-after"]; passedWarnAfter != nil {
-		warnAfter, err = strconv.Atoi(passedWarnAfter.(string))
-		if err != nil {
-			return err
-		}
-	}
-
 	active := parsedArgs["--active"].(bool)
 	if active {
-		activeSprint, err := jira.GetJiraActiveSprint(ctx, jiraClient, fmt.Sprintf("%d", board.ID), logger)
-		if err != nil || activeSprint == nil {
-			return fmt.Errorf("failed to get jira active sprint")
+		// boards with parallel sprints have more than one active sprint
+		activeSprints, err := jira.GetJiraActiveSprints(ctx, jiraClient, fmt.Sprintf("%d", board.ID), logger)
+		if err != nil {
+			return err
 		}
-		jql = fmt.Sprintf("Status NOT IN (Resolved,Closed) and sprint = %s and reporter = %s", activeSprint.Name, username)
+		jql = fmt.Sprintf("Status NOT IN (Resolved,Closed) and %s and reporter = %s", jira.SprintsJQL(activeSprints), username)
 	} else if sprintName != "" {
-		sprint, err := jira.GetJiraSprint(ctx, jiraClient, fmt.Sprintf("%d", board.ID), sprintName, logger)
-		if err != nil || sprint == nil {
-			return fmt.Errorf("%s", fmt.Sprintf("failed to get jira sprint %s", sprintName))
+		if _, err := jira.GetJiraSprint(ctx, jiraClient, fmt.Sprintf("%d", board.ID), sprintName, logger); err != nil {
+			return err
 		}
 		jql = fmt.Sprintf("Status NOT IN (Resolved,Closed) and sprint = %s and reporter = %s", sprintName, username)
 	} else {
 		jql = fmt.Sprintf("Status NOT IN (Resolved,Closed) and reporter = %s", username)
 	}
 
-	return jira.DisplayJiraIssues(ctx, jiraClient, jql, warnAfter, logger)
+	return jira.DisplayJiraIssues(ctx, jiraClient, jql, displayOptions, logger)
 }
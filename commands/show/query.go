@@ -0,0 +1,74 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/commands/utils"
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Query displays information about issues matching an arbitrary JQL query
+func Query(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils show query --jql=<expr> [--warn-after=<list>] [--page-size=<n>] [--max-results=<n>] [--columns=<list>] [--sort-by=<list>] [--group-by=<field>] [--output=<format>|--template=<template>|--template-file=<file>]
+Options:
+  -h --help               Show this screen.
+     --jql=<expr>         JQL query, e.g. "project = CLOUDSTACK and labels = flaky ORDER BY updated DESC".
+     --warn-after=<list>  Highlights issues in a status for too long: days In Progress (e.g. 3) or list of <status>=<days> (e.g. "In Review=2,Blocked=5").
+     --page-size=<n>      Number of issues fetched per request to Jira (50 by default).
+     --max-results=<n>    Show at most this number of issues (all matching issues by default).
+     --columns=<list>     Comma separated list of columns to display (key,summary,status,updated,assignee by default).
+                          Any of key, summary, status, updated, assignee, reporter, priority, type, components, labels,
+                          fixVersions, created, dueDate, storyPoints, epicLink, sprint or a custom field ID or name.
+     --sort-by=<list>     Comma separated list of columns to sort issues by, each optionally followed by :asc or :desc
+                          (e.g. priority,updated:desc). Any column accepted by --columns can be used.
+     --group-by=<field>   Group issues by status, assignee, priority, component or epic, with per group counts and subtotals.
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
+
+Description:
+  The show query command shows information about jira issues matching the JQL query passed with --jql.
+  Query is run as is: no project, board or user filter is added.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	if err := utils.VerifySettings(parsedArgs); err != nil {
+		return err
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	jql := strings.TrimSpace(parsedArgs["--jql"].(string))
+	if jql == "" {
+		return fmt.Errorf("--jql must not be empty")
+	}
+
+	logger := klogr.New()
+
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	return jira.DisplayJiraIssues(ctx, jiraClient, jql, displayOptions, logger)
+}
@@ -0,0 +1,110 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"k8s.io/klog/v2/klogr"
+
+	"github.com/gianlucam76/jira_utils/commands/utils"
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// Saved displays information about issues matching a saved query
+func Saved(ctx context.Context, args []string) error {
+	doc := `Usage:
+	jira-utils show saved --list
+	jira-utils show saved <name> [--project=<name>] [--board=<name>] [--username=<name>] [--warn-after=<list>] [--page-size=<n>] [--max-results=<n>] [--columns=<list>] [--sort-by=<list>] [--group-by=<field>] [--output=<format>|--template=<template>|--template-file=<file>]
+Options:
+  -h --help               Show this screen.
+     --list               List all saved queries.
+     --project=<name>	  Project used for {{.Project}} and {{.ActiveSprint}} (value in JIRA_PROJECT or config profile will be used by default)
+     --board=<name>       Board used for {{.ActiveSprint}} (value in JIRA_BOARD or config profile will be used by default)
+     --username=<name>    User used for {{.Me}} (by default user defined in env variable JIRA_USERNAME)
+     --warn-after=<list>  Highlights issues in a status for too long: days In Progress (e.g. 3) or list of <status>=<days> (e.g. "In Review=2,Blocked=5").
+     --page-size=<n>      Number of issues fetched per request to Jira (50 by default).
+     --max-results=<n>    Show at most this number of issues (all matching issues by default).
+     --columns=<list>     Comma separated list of columns to display (key,summary,status,updated,assignee by default).
+                          Any of key, summary, status, updated, assignee, reporter, priority, type, components, labels,
+                          fixVersions, created, dueDate, storyPoints, epicLink, sprint or a custom field ID or name.
+     --sort-by=<list>     Comma separated list of columns to sort issues by, each optionally followed by :asc or :desc
+                          (e.g. priority,updated:desc). Any column accepted by --columns can be used.
+     --group-by=<field>   Group issues by status, assignee, priority, component or epic, with per group counts and subtotals.
+     --output=<format>    Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
+
+Description:
+  The show saved command shows information about jira issues matching a query saved in the config file
+  (~/.config/jira_utils/config.yaml or the file in env variable JIRA_UTILS_CONFIG).
+  Saved query JQL can contain the placeholders {{.Me}}, {{.Project}} and {{.ActiveSprint}}.
+`
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Errorf(
+			"invalid option: 'jira-utils %s'. Use flag '--help' to read about a specific subcommand. Error: %v",
+			strings.Join(args, " "),
+			err,
+		)
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	config := jira.ActiveConfig()
+
+	if parsedArgs["--list"].(bool) {
+		jira.DisplaySavedQueries(config, os.Stdout)
+		return nil
+	}
+
+	name := parsedArgs["<name>"].(string)
+	query, err := jira.GetSavedQuery(config, name)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.VerifySettings(parsedArgs); err != nil {
+		return err
+	}
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
+	logger := klogr.New()
+
+	// if not passed, username setting is used (only if needed by the query)
+	username := ""
+	if passedUsername := parsedArgs["--username"]; passedUsername != nil {
+		username = passedUsername.(string)
+	}
+
+	projectName := ""
+	if passedProject := parsedArgs["--project"]; passedProject != nil {
+		projectName = passedProject.(string)
+	}
+
+	boardName := ""
+	if passedBoard := parsedArgs["--board"]; passedBoard != nil {
+		boardName = passedBoard.(string)
+	}
+
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	variables := jira.NewQueryVariables(ctx, jiraClient, username, projectName, boardName, logger)
+	jql, err := jira.ExpandQuery(name, query, variables)
+	if err != nil {
+		return err
+	}
+
+	return jira.DisplayJiraIssues(ctx, jiraClient, jql, displayOptions, logger)
+}
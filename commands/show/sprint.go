@@ -3,29 +3,39 @@ package show
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
 	docopt "github.com/docopt/docopt-go"
-	"github.com/olekukonko/tablewriter"
 	"k8s.io/klog/v2/klogr"
 
+	"github.com/gianlucam76/jira_utils/commands/utils"
 	"github.com/gianlucam76/jira_utils/jira"
 )
 
-// Sprints displays information about issues in a given sprint
+// Sprints displays the sprints of a board
 func Sprints(ctx context.Context, args []string) error {
 	doc := `Usage:
-	jira-utils show sprints [--project=<name>] [--board=<name>]
+	jira-utils show sprints [--project=<name>] [--board=<name>] [--state=<states>] [--since=<date>] [--done-statuses=<list>] [--output=<format>|--template=<template>|--template-file=<file>]
 Options:
-  -h --help       Show this screen.
-     --project    Show Jira issues in current project (value in JIRA_PROJECT will be used by default)
-     --board      Show Jira issues in current project/board (value in JIRA_BOARD will be used by default)
+  -h --help          Show this screen.
+     --project=<name>  Show Jira issues in current project (value in JIRA_PROJECT or config profile will be used by default)
+     --board=<name>    Show Jira issues in current project/board (value in JIRA_BOARD or config profile will be used by default)
+     --state=<states>  Show only sprints in these states (comma separated): active, future or closed.
+                       Without --state and --since only active and future sprints are shown.
+     --since=<date>    Show only sprints not ended before date (e.g. 2022-04-01) or age (e.g. 30d).
+     --done-statuses=<list>  Comma separated statuses of completed issues [default: Resolved,Closed,Done].
+     --output=<format>  Output format: table, json, yaml, csv, tsv or markdown [default: table].
+     --template=<template>  Display results with a Go text/template (see README.md).
+     --template-file=<file>  Display results with the Go text/template in file.
 
 Description:
-  The show sprints command shows information about jira issues.
+  The show sprints command shows the sprints of a board with start, end and complete dates, goal, days remaining
+  (active sprints only), and number of issues and story points not done and done.
+  Only sprints in the states passed with --state are requested to Jira: pass --state=closed or --since
+  to list closed sprints.
 `
-	parsedArgs, err := docopt.ParseArgs(doc, nil, "1.0")
+	parsedArgs, err := docopt.ParseArgs(doc, args, "1.0")
 	if err != nil {
 		fmt.Println(err)
 		return fmt.Errorf(
@@ -38,9 +48,38 @@ Description:
 		return nil
 	}
 
+	if err := utils.VerifySettings(parsedArgs, jira.ProjectSetting, jira.BoardSetting); err != nil {
+		return err
+	}
+
+	filter := &jira.SprintFilter{}
+	if passedState := parsedArgs["--state"]; passedState != nil {
+		filter.States, err = jira.ParseSprintStates(passedState.(string))
+		if err != nil {
+			return fmt.Errorf("--state: %w", err)
+		}
+	}
+	if passedSince := parsedArgs["--since"]; passedSince != nil {
+		filter.Since, err = jira.ParseSince(passedSince.(string), time.Now())
+		if err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+	}
+	// listing (and counting the issues of) the whole board history is opt-in
+	if len(filter.States) == 0 && filter.Since.IsZero() {
+		filter.States = []string{jira.SprintActive, jira.SprintFuture}
+	}
+
+	doneStatuses := jira.ParseDoneStatuses(parsedArgs["--done-statuses"].(string))
+
+	displayOptions, err := utils.GetDisplayOptions(parsedArgs)
+	if err != nil {
+		return err
+	}
+
 	logger := klogr.New()
 
-	jiraClient, err := jira.GetJiraClient(ctx, jira.GetUsername(logger), jira.GetPassword(logger), logger)
+	jiraClient, err := utils.GetJiraClient(ctx, logger)
 	if err != nil {
 		return err
 	}
@@ -51,8 +90,8 @@ Description:
 	}
 
 	project, err := jira.GetJiraProject(ctx, jiraClient, projectName, logger)
-	if err != nil || project == nil {
-		return fmt.Errorf("failed to get jira project")
+	if err != nil {
+		return err
 	}
 
 	boardName := ""
@@ -61,24 +100,10 @@ Description:
 	}
 
 	board, err := jira.GetJiraBoard(ctx, jiraClient, project.Key, boardName, logger)
-	if err != nil || board == nil {
-		return fmt.Errorf("failed to get jira board")
-	}
-
-	sprints, err := jira.GetJiraSprints(ctx, jiraClient, fmt.Sprintf("%d", board.ID), logger)
 	if err != nil {
-		return fmt.Errorf("failed to get jira sprints")
-	}
-
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"SPRINT", "STATE"})
-	table.SetReflowDuringAutoWrap(false)
-	table.SetRowLine(true)
-
-	for i := range sprints {
-		table.Append([]string{sprints[i].Name, sprints[i].State})
+		return err
 	}
 
-	table.Render()
-	return nil
+	return jira.DisplayJiraSprints(ctx, jiraClient, fmt.Sprintf("%d", board.ID), filter, doneStatuses,
+		displayOptions, logger)
 }
@@ -0,0 +1,122 @@
+// Package utils contains the helpers shared by show and report subcommands
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/go-logr/logr"
+
+	"github.com/gianlucam76/jira_utils/jira"
+)
+
+// GetJiraClient returns the client used by all show and report subcommands.
+// Unit tests can replace it to return a fake (see package jira/fake).
+var GetJiraClient = func(ctx context.Context, logger logr.Logger) (jira.JiraAPI, error) {
+	credentials, err := jira.GetCredentials(logger)
+	if err != nil {
+		return nil, err
+	}
+	return jira.GetJiraClient(ctx, credentials, logger)
+}
+
+// VerifySettings returns an error if any setting needed by the subcommand is not
+// set. Settings needed to access Jira are always verified, names (e.g. project,
+// board) only if not passed with the flag of the same name.
+func VerifySettings(parsedArgs docopt.Opts, names ...string) error {
+	needed := jira.AuthSettings()
+	for _, name := range names {
+		if parsedArgs["--"+name] == nil {
+			needed = append(needed, name)
+		}
+	}
+	return jira.VerifySettings(needed...)
+}
+
+// GetDisplayOptions returns the display options set with --output, --template,
+// --template-file, --columns, --sort-by, --group-by, --warn-after, --page-size and --max-results
+// (when supported by the subcommand)
+func GetDisplayOptions(parsedArgs docopt.Opts) (*jira.DisplayOptions, error) {
+	options := &jira.DisplayOptions{}
+
+	var err error
+	if passedOutput := parsedArgs["--output"]; passedOutput != nil {
+		options.Output, err = jira.ParseOutputFormat(passedOutput.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if passedTemplate := parsedArgs["--template"]; passedTemplate != nil {
+		options.Template, err = jira.ParseTemplate("template", passedTemplate.(string))
+		if err != nil {
+			return nil, err
+		}
+	} else if passedTemplateFile := parsedArgs["--template-file"]; passedTemplateFile != nil {
+		options.Template, err = jira.ParseTemplateFile(passedTemplateFile.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if options.Template != nil && jira.TemplateNeedsChangelog(options.Template) {
+		options.Expand = "changelog"
+	}
+
+	if passedColumns := parsedArgs["--columns"]; passedColumns != nil {
+		options.Columns = strings.Split(passedColumns.(string), ",")
+	}
+
+	if passedSortBy := parsedArgs["--sort-by"]; passedSortBy != nil {
+		options.SortBy, err = jira.ParseSortKeys(passedSortBy.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if passedGroupBy := parsedArgs["--group-by"]; passedGroupBy != nil {
+		options.GroupBy = passedGroupBy.(string)
+		if err := jira.ValidateGroupBy(options.GroupBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if passedWarnAfter, supported := parsedArgs["--warn-after"]; supported {
+		// warnAfter setting is the default for subcommands supporting --warn-after
+		warnAfter, _ := jira.LookupSetting(jira.WarnAfterSetting)
+		if passedWarnAfter != nil {
+			warnAfter = passedWarnAfter.(string)
+		}
+		options.WarnAfter, err = jira.ParseWarnThresholds(warnAfter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if passedPageSize := parsedArgs["--page-size"]; passedPageSize != nil {
+		options.PageSize, err = parsePositiveInt("--page-size", passedPageSize.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if passedMaxResults := parsedArgs["--max-results"]; passedMaxResults != nil {
+		options.MaxIssues, err = parsePositiveInt("--max-results", passedMaxResults.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return options, nil
+}
+
+// parsePositiveInt parses value of flag, returning an error unless it is a number greater than 0
+func parsePositiveInt(flag, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a number greater than 0, got %q", flag, value)
+	}
+	return n, nil
+}
@@ -8,6 +8,8 @@ require (
 	github.com/fatih/color v1.13.0
 	github.com/go-logr/logr v1.2.3
 	github.com/olekukonko/tablewriter v0.0.5
+	golang.org/x/crypto v0.0.0-20220214200702-86341886e292
+	gopkg.in/yaml.v3 v3.0.1
 	k8s.io/klog/v2 v2.60.1
 )
 
@@ -0,0 +1,43 @@
+// fakejira serves a fake Jira REST API backed by a YAML/JSON fixture.
+// It can be used to try jira_utils without a Jira server:
+//
+//	go run ./hack/fakejira --fixture=hack/fakejira/fixture.yaml --address=127.0.0.1:8080
+//	export JIRA_BASE_URL=http://127.0.0.1:8080
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/gianlucam76/jira_utils/jira/fake"
+)
+
+func main() {
+	doc := `Usage:
+	fakejira --fixture=<file> [--address=<address>]
+Options:
+  -h --help              Show this screen.
+     --fixture=<file>    YAML or JSON file with the dataset to serve.
+     --address=<address> Address to listen on [default: 127.0.0.1:8080].
+`
+	parsedArgs, err := docopt.ParseArgs(doc, nil, "1.0")
+	if err != nil {
+		os.Exit(1)
+	}
+
+	client, err := fake.LoadFixture(parsedArgs["--fixture"].(string))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	address := parsedArgs["--address"].(string)
+	fmt.Printf("Serving fake Jira on http://%s\n", address)
+	if err := http.ListenAndServe(address, fake.NewHandler(client)); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,207 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// JiraAPI is the subset of the Jira REST API used by this package.
+// The go-jira client is one implementation (see NewJiraAPI); package
+// jira/fake contains an in-memory one which can be used in unit tests.
+type JiraAPI interface {
+	// GetProject returns the project with key or name projectName
+	GetProject(ctx context.Context, projectName string) (*jira.Project, error)
+	// GetAllBoards returns all boards matching options, fetching all pages
+	GetAllBoards(ctx context.Context, options *jira.BoardListOptions) ([]jira.Board, error)
+	// GetAllSprints returns all sprints of board boardID matching options (e.g. in a state), fetching all pages
+	GetAllSprints(ctx context.Context, boardID string, options *jira.GetAllSprintsOptions) ([]Sprint, error)
+	// SearchIssues returns one page of the issues matching jql (as defined by
+	// options StartAt and MaxResults) and the total number of matching issues
+	SearchIssues(ctx context.Context, jql string, options *jira.SearchOptions) ([]jira.Issue, int, error)
+	// GetIssue returns the issue with ID (or key) issueID
+	GetIssue(ctx context.Context, issueID string, options *jira.GetQueryOptions) (*jira.Issue, error)
+	// CreateIssue creates a new issue
+	CreateIssue(ctx context.Context, issue *jira.Issue) (*jira.Issue, error)
+	// AddComment adds comment to issue issueID
+	AddComment(ctx context.Context, issueID string, comment *jira.Comment) (*jira.Comment, error)
+	// MoveIssuesToSprint moves issues to sprint sprintID
+	MoveIssuesToSprint(ctx context.Context, sprintID int, issueIDs []string) error
+	// GetTransitions returns the transitions currently available for issue issueID
+	GetTransitions(ctx context.Context, issueID string) ([]jira.Transition, error)
+	// DoTransition moves issue issueID through transition transitionID
+	DoTransition(ctx context.Context, issueID, transitionID string) error
+	// GetFields returns all system and custom issue fields
+	GetFields(ctx context.Context) ([]jira.Field, error)
+}
+
+// Sprint is a sprint of an agile board, with its goal (not decoded by go-jira)
+type Sprint struct {
+	jira.Sprint
+	Goal string `json:"goal,omitempty"`
+}
+
+// sprintList is a page of sprints, as returned by GET rest/agile/1.0/board/{boardId}/sprint
+type sprintList struct {
+	IsLast bool     `json:"isLast"`
+	Values []Sprint `json:"values"`
+}
+
+// goJiraClient implements JiraAPI using a go-jira client
+type goJiraClient struct {
+	client *jira.Client
+}
+
+// NewJiraAPI returns a JiraAPI backed by the passed in go-jira client
+func NewJiraAPI(client *jira.Client) JiraAPI {
+	return &goJiraClient{client: client}
+}
+
+func (c *goJiraClient) GetProject(ctx context.Context, projectName string) (*jira.Project, error) {
+	project, resp, err := c.client.Project.GetWithContext(ctx, projectName)
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	return project, nil
+}
+
+func (c *goJiraClient) GetAllBoards(ctx context.Context, options *jira.BoardListOptions) ([]jira.Board, error) {
+	// boards are returned one page at a time
+	pageOptions := jira.BoardListOptions{}
+	if options != nil {
+		pageOptions = *options
+	}
+	boards := make([]jira.Board, 0)
+	for {
+		boardList, resp, err := c.client.Board.GetAllBoardsWithContext(ctx, &pageOptions)
+		if err != nil {
+			return nil, apiError(resp, err)
+		}
+		boards = append(boards, boardList.Values...)
+		if boardList.IsLast || len(boardList.Values) == 0 {
+			return boards, nil
+		}
+		pageOptions.StartAt += len(boardList.Values)
+	}
+}
+
+func (c *goJiraClient) GetAllSprints(ctx context.Context, boardID string, options *jira.GetAllSprintsOptions) ([]Sprint, error) {
+	if _, err := strconv.Atoi(boardID); err != nil {
+		return nil, fmt.Errorf("invalid board ID %q: %w", boardID, err)
+	}
+
+	// go-jira BoardService returns only the first page, without goals:
+	// sprints are requested directly, one page at a time
+	query := url.Values{}
+	if options != nil {
+		if options.State != "" {
+			query.Set("state", options.State)
+		}
+		if options.MaxResults > 0 {
+			query.Set("maxResults", strconv.Itoa(options.MaxResults))
+		}
+	}
+	sprints := make([]Sprint, 0)
+	for {
+		query.Set("startAt", strconv.Itoa(len(sprints)))
+		endpoint := fmt.Sprintf("rest/agile/1.0/board/%s/sprint?%s", boardID, query.Encode())
+		req, err := c.client.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		page := &sprintList{}
+		resp, err := c.client.Do(req, page)
+		if err != nil {
+			if resp != nil {
+				return nil, apiError(resp, jira.NewJiraError(resp, err))
+			}
+			return nil, apiError(resp, err)
+		}
+		sprints = append(sprints, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return sprints, nil
+		}
+	}
+}
+
+func (c *goJiraClient) SearchIssues(ctx context.Context, jql string, options *jira.SearchOptions) ([]jira.Issue, int, error) {
+	issues, resp, err := c.client.Issue.SearchWithContext(ctx, jql, options)
+	if err != nil {
+		return nil, 0, apiError(resp, err)
+	}
+	return issues, resp.Total, nil
+}
+
+func (c *goJiraClient) GetIssue(ctx context.Context, issueID string, options *jira.GetQueryOptions) (*jira.Issue, error) {
+	issue, resp, err := c.client.Issue.GetWithContext(ctx, issueID, options)
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	return issue, nil
+}
+
+func (c *goJiraClient) CreateIssue(ctx context.Context, issue *jira.Issue) (*jira.Issue, error) {
+	created, resp, err := c.client.Issue.CreateWithContext(ctx, issue)
+	if err != nil {
+		// differently from other go-jira methods, CreateWithContext
+		// does not add the response body to the returned error
+		if resp != nil {
+			return nil, apiError(resp, jira.NewJiraError(resp, err))
+		}
+		return nil, apiError(resp, err)
+	}
+	return created, nil
+}
+
+func (c *goJiraClient) AddComment(ctx context.Context, issueID string, comment *jira.Comment) (*jira.Comment, error) {
+	added, resp, err := c.client.Issue.AddCommentWithContext(ctx, issueID, comment)
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	return added, nil
+}
+
+func (c *goJiraClient) MoveIssuesToSprint(ctx context.Context, sprintID int, issueIDs []string) error {
+	resp, err := c.client.Sprint.MoveIssuesToSprintWithContext(ctx, sprintID, issueIDs)
+	return apiError(resp, err)
+}
+
+func (c *goJiraClient) GetTransitions(ctx context.Context, issueID string) ([]jira.Transition, error) {
+	transitions, resp, err := c.client.Issue.GetTransitionsWithContext(ctx, issueID)
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	return transitions, nil
+}
+
+func (c *goJiraClient) DoTransition(ctx context.Context, issueID, transitionID string) error {
+	resp, err := c.client.Issue.DoTransitionWithContext(ctx, issueID, transitionID)
+	return apiError(resp, err)
+}
+
+func (c *goJiraClient) GetFields(ctx context.Context) ([]jira.Field, error) {
+	fields, resp, err := c.client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	return fields, nil
+}
+
+// apiError returns err, wrapped in an error matching ErrNotFound if Jira
+// answered 404 Not Found (e.g. unknown project, board or issue).
+// If Jira rejected the credentials, the AuthError is returned as is.
+func apiError(resp *jira.Response, err error) error {
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		return authErr
+	}
+	if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package jira_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+
+	jirautils "github.com/gianlucam76/jira_utils/jira"
+	"github.com/gianlucam76/jira_utils/jira/fake"
+)
+
+func TestIssueLifecycle(t *testing.T) {
+	ctx := context.TODO()
+	logger := logr.Discard()
+	client := fake.NewClient()
+	client.Projects = []jira.Project{{ID: "10000", Key: "CLOUDSTACK", Name: "CloudStack"}}
+
+	project, err := jirautils.GetJiraProject(ctx, client, "CloudStack", logger)
+	if err != nil {
+		t.Fatalf("GetJiraProject: %v", err)
+	}
+	if project.Key != "CLOUDSTACK" {
+		t.Errorf("got project %s, want CLOUDSTACK", project.Key)
+	}
+	if _, err := jirautils.GetJiraProject(ctx, client, "KUBE", logger); err == nil {
+		t.Errorf("got no error getting a project which does not exist")
+	}
+
+	created, err := jirautils.CreateIssue(ctx, client, nil, &jira.Priority{Name: "Major"}, project.Key, "UI",
+		"mgianluc", "TestLogin", "TestLogin failed", logger)
+	if err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	issue, err := client.GetIssue(ctx, created.Key, nil)
+	if err != nil {
+		t.Fatalf("created issue %s not found: %v", created.Key, err)
+	}
+	fields := issue.Fields
+	if fields.Type.Name != "Bug" || fields.Project.Key != "CLOUDSTACK" || fields.Assignee.Name != "mgianluc" ||
+		fields.Summary != "TestLogin failed" || fields.Description != "Test TestLogin failed" ||
+		len(fields.Components) != 1 || fields.Components[0].Name != "UI" || fields.Priority.Name != "Major" {
+		t.Errorf("got issue fields %+v", fields)
+	}
+
+	if err := jirautils.AddCommentToIssue(ctx, client, issue.ID, "failed again", logger); err != nil {
+		t.Fatalf("AddCommentToIssue: %v", err)
+	}
+	if comments := client.Comments[issue.ID]; len(comments) != 1 || comments[0].Body != "failed again" {
+		t.Errorf("got comments %+v, want one comment", comments)
+	}
+
+	// start progress -> resolved
+	client.Transitions[issue.ID] = []jira.Transition{
+		{ID: "4", Name: "Start Progress", To: jira.Status{Name: "In Progress"}},
+		{ID: "5", Name: "Resolve", To: jira.Status{Name: "Resolved"}},
+	}
+	if err := jirautils.ResolveIssue(ctx, client, issue, logger); err != nil {
+		t.Fatalf("ResolveIssue: %v", err)
+	}
+	if resolved, _ := client.GetIssue(ctx, issue.ID, nil); resolved.Fields.Status.Name != "Resolved" {
+		t.Errorf("got status %s, want Resolved", resolved.Fields.Status.Name)
+	}
+
+	client.Transitions[issue.ID] = []jira.Transition{{ID: "1", Name: "Reopen", To: jira.Status{Name: "Open"}}}
+	if err := jirautils.ResolveIssue(ctx, client, issue, logger); err == nil {
+		t.Errorf("got no error resolving an issue without known transitions")
+	}
+}
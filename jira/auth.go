@@ -0,0 +1,237 @@
+package jira
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+// AuthType is the way jira_utils authenticates to Jira
+type AuthType string
+
+const (
+	// AuthBasic uses username and password (base64 encoded in settings)
+	AuthBasic = AuthType("basic")
+	// AuthBearer uses a personal access token (Jira Data Center/Server 8.14+)
+	AuthBearer = AuthType("bearer")
+	// AuthCloud uses the account email as username and an API token (Jira Cloud)
+	AuthCloud = AuthType("cloud")
+	// AuthCookie uses a session cookie: either the one passed as token
+	// (e.g. "JSESSIONID=..."), or one obtained logging in with username and password
+	AuthCookie = AuthType("cookie")
+	// AuthOAuth uses the OAuth 2.0 token obtained with OAuthLogin, refreshed when expired
+	AuthOAuth = AuthType("oauth")
+	// AuthOAuth1 signs requests with the OAuth 1.0a token obtained with OAuth1Login
+	// (Jira Data Center/Server application links)
+	AuthOAuth1 = AuthType("oauth1")
+)
+
+// authSessionPath is the Jira endpoint used to log in with AuthCookie
+const authSessionPath = "rest/auth/1/session"
+
+// authHints explain, for each AuthType, what to check when Jira rejects the credentials
+var authHints = map[AuthType]string{
+	AuthBasic: "check username and password. Jira Cloud does not accept passwords: use auth cloud with an API token",
+	AuthBearer: "check the personal access token is valid and not expired. " +
+		"Personal access tokens are supported by Jira Data Center/Server 8.14+ only (use auth cloud for Jira Cloud)",
+	AuthCloud: "check username is the account email and token an API token created at " +
+		"https://id.atlassian.com/manage-profile/security/api-tokens",
+	AuthCookie: "check the session cookie is not expired or, when logging in, username and password",
+	AuthOAuth:  "the OAuth token was revoked or lacks the needed scopes: run 'jira-utils auth login'",
+	AuthOAuth1: "the OAuth token was revoked, or the application link consumer key or public key changed: " +
+		"run 'jira-utils auth login'",
+}
+
+// ParseAuthType returns the AuthType with passed name. Empty name means AuthBasic.
+func ParseAuthType(name string) (AuthType, error) {
+	switch t := AuthType(strings.ToLower(name)); t {
+	case "":
+		return AuthBasic, nil
+	case AuthBasic, AuthBearer, AuthCloud, AuthCookie, AuthOAuth, AuthOAuth1:
+		return t, nil
+	}
+	return "", fmt.Errorf("unsupported auth %q (supported: %s, %s, %s, %s, %s, %s)", name,
+		AuthBasic, AuthBearer, AuthCloud, AuthCookie, AuthOAuth, AuthOAuth1)
+}
+
+// requiredSettings returns the settings needed to authenticate with authType
+func (t AuthType) requiredSettings() []string {
+	switch t {
+	case AuthBearer:
+		return []string{TokenSetting}
+	case AuthCloud:
+		return []string{UsernameSetting, TokenSetting}
+	case AuthCookie:
+		if token, _ := LookupSetting(TokenSetting); token != "" {
+			return []string{TokenSetting}
+		}
+		return []string{UsernameSetting, PasswordSetting}
+	case AuthOAuth:
+		return []string{OAuthClientIDSetting, OAuthClientSecretSetting}
+	case AuthOAuth1:
+		return []string{OAuthClientIDSetting, OAuthPrivateKeySetting}
+	default:
+		return []string{UsernameSetting, PasswordSetting}
+	}
+}
+
+// Credentials are used to authenticate to Jira
+type Credentials struct {
+	Type     AuthType
+	Username string
+	// Password is the decoded password
+	Password string
+	// Token is the personal access token, API token or session cookie, depending on Type
+	Token string
+	// OAuthToken is the cached OAuth token (AuthOAuth and AuthOAuth1 only)
+	OAuthToken *OAuthToken
+
+	// oauthClientID is the OAuth 2.0 client ID or the OAuth 1.0a consumer key
+	oauthClientID   string
+	oauthPrivateKey *rsa.PrivateKey
+}
+
+// GetCredentials returns the credentials set in settings (see LookupSetting)
+// for the auth type in use. Secrets not set in settings are read from the
+// secret store in use, if any.
+func GetCredentials(logger logr.Logger) (*Credentials, error) {
+	authName, _ := LookupSetting(AuthSetting)
+	authType, err := ParseAuthType(authName)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifySettings(authType.requiredSettings()...); err != nil {
+		return nil, err
+	}
+
+	credentials := &Credentials{Type: authType}
+	if authType == AuthOAuth || authType == AuthOAuth1 {
+		baseURL, _ := LookupSetting(BaseURLSetting)
+		credentials.OAuthToken, err = LoadOAuthToken(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		// client secret, only needed to refresh the token, is read when needed
+		credentials.oauthClientID, _ = LookupSetting(OAuthClientIDSetting)
+		if authType == AuthOAuth1 {
+			privateKey, _ := LookupSetting(OAuthPrivateKeySetting)
+			if credentials.oauthPrivateKey, err = LoadOAuthPrivateKey(privateKey); err != nil {
+				return nil, err
+			}
+		}
+		return credentials, nil
+	}
+
+	credentials.Username, _ = LookupSetting(UsernameSetting)
+	switch authType {
+	case AuthBearer, AuthCloud:
+		credentials.Token, err = LookupSecret(TokenSetting)
+	case AuthCookie:
+		// session cookie is never read from the secret store: it is short lived
+		if credentials.Token, _ = LookupSetting(TokenSetting); credentials.Token == "" {
+			credentials.Password, err = LookupSecret(PasswordSetting)
+		}
+	default:
+		credentials.Password, err = LookupSecret(PasswordSetting)
+	}
+	if err != nil {
+		logger.Info(fmt.Sprintf("Failed to get %s credentials: %v", authType, err))
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// httpClient returns an http.Client authenticating to Jira at baseURL with credentials,
+// sending requests with base
+func (c *Credentials) httpClient(baseURL string, base http.RoundTripper) *http.Client {
+	transport := &authCheckTransport{authType: c.Type, transport: base}
+
+	switch c.Type {
+	case AuthBearer:
+		return (&jira.BearerAuthTransport{Token: c.Token, Transport: transport}).Client()
+	case AuthCloud:
+		return (&jira.BasicAuthTransport{Username: c.Username, Password: c.Token, Transport: transport}).Client()
+	case AuthOAuth:
+		return &http.Client{Transport: &oauthTransport{baseURL: baseURL, clientID: c.oauthClientID,
+			token: c.OAuthToken, transport: transport}}
+	case AuthOAuth1:
+		return &http.Client{Transport: &oauth1Transport{baseURL: baseURL,
+			signer: &oauth1Signer{consumerKey: c.oauthClientID, privateKey: c.oauthPrivateKey},
+			token:  c.OAuthToken, transport: transport}}
+	case AuthCookie:
+		if c.Token != "" {
+			return &http.Client{Transport: &sessionCookieTransport{cookie: c.Token, transport: transport}}
+		}
+		return (&jira.CookieAuthTransport{Username: c.Username, Password: c.Password,
+			AuthURL: strings.TrimSuffix(baseURL, "/") + "/" + authSessionPath, Transport: transport}).Client()
+	default:
+		return (&jira.BasicAuthTransport{Username: c.Username, Password: c.Password, Transport: transport}).Client()
+	}
+}
+
+// AuthError is returned when Jira rejects the credentials. It matches ErrAuthFailed.
+type AuthError struct {
+	Type       AuthType
+	StatusCode int
+	// Reason is the login failure reason reported by Jira, if any
+	Reason string
+}
+
+func (e *AuthError) Error() string {
+	msg := fmt.Sprintf("jira rejected %s credentials (HTTP %d", e.Type, e.StatusCode)
+	if e.Reason != "" {
+		msg += ", " + e.Reason
+	}
+	msg += "): " + authHints[e.Type]
+	return msg
+}
+
+// Is makes errors.Is(err, ErrAuthFailed) true
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuthFailed
+}
+
+// authCheckTransport turns responses reporting rejected credentials into an AuthError
+type authCheckTransport struct {
+	authType  AuthType
+	transport http.RoundTripper
+}
+
+func (t *authCheckTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	// Jira Server reports login failures in X-Seraph-LoginReason, e.g. AUTHENTICATED_FAILED,
+	// or AUTHENTICATION_DENIED when a CAPTCHA is required after too many failures
+	reason := resp.Header.Get("X-Seraph-LoginReason")
+	rejected := resp.StatusCode == http.StatusUnauthorized ||
+		(resp.StatusCode == http.StatusForbidden && strings.Contains(reason, "AUTHENTICATION_DENIED"))
+	if !rejected {
+		return resp, nil
+	}
+
+	resp.Body.Close()
+	return nil, &AuthError{Type: t.authType, StatusCode: resp.StatusCode, Reason: reason}
+}
+
+// sessionCookieTransport adds a session cookie (e.g. "JSESSIONID=...") to all requests
+type sessionCookieTransport struct {
+	cookie    string
+	transport http.RoundTripper
+}
+
+func (t *sessionCookieTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context()) // per RoundTripper contract
+	if existing := req2.Header.Get("Cookie"); existing != "" {
+		req2.Header.Set("Cookie", existing+"; "+t.cookie)
+	} else {
+		req2.Header.Set("Cookie", t.cookie)
+	}
+	return t.transport.RoundTrip(req2)
+}
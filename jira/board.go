@@ -0,0 +1,140 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+// BoardMatch is how board names are matched
+type BoardMatch string
+
+const (
+	// BoardMatchExact matches boards whose name is the passed name, ignoring case
+	BoardMatchExact = BoardMatch("exact")
+	// BoardMatchSubstring matches boards whose name contains the passed name, ignoring case
+	BoardMatchSubstring = BoardMatch("substring")
+)
+
+// BoardFilter selects boards listed by FindJiraBoards
+type BoardFilter struct {
+	// Name, if set, is matched against board names as defined by Match
+	// (BoardMatchSubstring if not set)
+	Name  string
+	Match BoardMatch
+	// Type, if set, is the board type: scrum or kanban
+	Type string
+}
+
+// matches returns true if boardName matches filter
+func (f *BoardFilter) matches(boardName string) bool {
+	if f.Name == "" {
+		return true
+	}
+	if f.Match == BoardMatchExact {
+		return strings.EqualFold(boardName, f.Name)
+	}
+	return strings.Contains(strings.ToLower(boardName), strings.ToLower(f.Name))
+}
+
+// AmbiguousBoardError is returned when more than one board matches the board name.
+// It matches ErrAmbiguousBoard.
+type AmbiguousBoardError struct {
+	Name    string
+	Project string
+	// Candidates are the boards matching Name
+	Candidates []jira.Board
+}
+
+func (e *AmbiguousBoardError) Error() string {
+	candidates := make([]string, len(e.Candidates))
+	for i := range e.Candidates {
+		candidates[i] = fmt.Sprintf("%s (id %d, %s)", e.Candidates[i].Name, e.Candidates[i].ID, e.Candidates[i].Type)
+	}
+	return fmt.Sprintf("%v: %d boards in project %s match %q: %s", ErrAmbiguousBoard, len(e.Candidates),
+		e.Project, e.Name, strings.Join(candidates, ", "))
+}
+
+// Is makes errors.Is(err, ErrAmbiguousBoard) true
+func (e *AmbiguousBoardError) Is(target error) bool {
+	return target == ErrAmbiguousBoard
+}
+
+// FindJiraBoards returns the boards of project projectKey (of all projects if
+// projectKey is empty) selected by filter, sorted by name
+func FindJiraBoards(ctx context.Context, jiraClient JiraAPI, projectKey string, filter *BoardFilter,
+	logger logr.Logger) ([]jira.Board, error) {
+	if filter == nil {
+		filter = &BoardFilter{}
+	}
+
+	// Jira name filter is a case insensitive substring match, exact matches are selected below
+	boardListOptions := &jira.BoardListOptions{ProjectKeyOrID: projectKey, Name: filter.Name, BoardType: filter.Type}
+	boards, err := jiraClient.GetAllBoards(ctx, boardListOptions)
+	if err != nil {
+		logger.Info(fmt.Sprintf("Failed to get board list. Error %v", err))
+		return nil, fmt.Errorf("failed to get boards of project %s: %w", projectKey, err)
+	}
+
+	matching := make([]jira.Board, 0, len(boards))
+	for i := range boards {
+		if filter.matches(boards[i].Name) {
+			matching = append(matching, boards[i])
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if matching[i].Name != matching[j].Name {
+			return matching[i].Name < matching[j].Name
+		}
+		return matching[i].ID < matching[j].ID
+	})
+	return matching, nil
+}
+
+// BoardList is the document displayed for a list of boards with json and yaml output.
+type BoardList struct {
+	Project string        `json:"project" yaml:"project"`
+	Count   int           `json:"count" yaml:"count"`
+	Boards  []BoardRecord `json:"boards" yaml:"boards"`
+}
+
+// BoardRecord is a board as displayed by json, yaml, csv and tsv output.
+type BoardRecord struct {
+	ID   int    `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"`
+}
+
+// boardRecordHeaders are the csv/tsv headers for BoardRecord
+var boardRecordHeaders = []string{"id", "name", "type"}
+
+func (r *BoardRecord) values() []string {
+	return []string{fmt.Sprintf("%d", r.ID), r.Name, r.Type}
+}
+
+// DisplayJiraBoards displays the boards of project projectKey selected by filter
+func DisplayJiraBoards(ctx context.Context, jiraClient JiraAPI, projectKey string, filter *BoardFilter,
+	options *DisplayOptions, logger logr.Logger) error {
+	if options == nil {
+		options = &DisplayOptions{}
+	}
+
+	boards, err := FindJiraBoards(ctx, jiraClient, projectKey, filter, logger)
+	if err != nil {
+		return err
+	}
+
+	list := &BoardList{Project: projectKey, Count: len(boards), Boards: make([]BoardRecord, len(boards))}
+	for i := range boards {
+		list.Boards[i] = BoardRecord{ID: boards[i].ID, Name: boards[i].Name, Type: boards[i].Type}
+	}
+
+	if options.Template != nil {
+		return options.Template.Execute(options.writer(), list)
+	}
+	return writeBoards(options.writer(), options.Output, list)
+}
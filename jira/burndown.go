@@ -0,0 +1,710 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+// BurndownUnit is the unit work is measured in by burndown charts
+type BurndownUnit string
+
+const (
+	// UnitPoints measures work in story points
+	UnitPoints = BurndownUnit("points")
+	// UnitIssues measures work in number of issues
+	UnitIssues = BurndownUnit("issues")
+)
+
+const (
+	// sprintField is the changelog field changed when an issue is added to or removed from a sprint
+	sprintField = "Sprint"
+	// storyPointsField is the name of the story points custom field
+	storyPointsField = "Story Points"
+	// chartHeight is the number of rows of ASCII charts
+	chartHeight = 12
+)
+
+// DefaultDoneStatuses are the statuses of completed issues when none is passed
+var DefaultDoneStatuses = []string{"Resolved", "Closed", "Done"}
+
+// BurndownOptions controls how the burndown is computed
+type BurndownOptions struct {
+	// Unit is the unit of the ideal line and of the charts (UnitPoints by default)
+	Unit BurndownUnit
+	// DoneStatuses are the statuses of completed issues (DefaultDoneStatuses by default)
+	DoneStatuses []string
+	// Project, if set, is the key of the project of the board. Its issues updated since the sprint
+	// started are searched for issues removed from the sprint, which searching by sprint misses.
+	Project string
+}
+
+// Burndown is the document displayed by DisplayBurndown with json and yaml output.
+type Burndown struct {
+	Sprint    string       `json:"sprint" yaml:"sprint"`
+	State     string       `json:"state" yaml:"state"`
+	StartDate time.Time    `json:"startDate" yaml:"startDate"`
+	EndDate   time.Time    `json:"endDate" yaml:"endDate"`
+	Unit      BurndownUnit `json:"unit" yaml:"unit"`
+	// Days has a point at sprint start, then one at the end of each day of the sprint until now
+	Days []BurndownDay `json:"days" yaml:"days"`
+	// Added and Removed are the issues added to or removed from the sprint after it started
+	Added   []ScopeChange `json:"added" yaml:"added"`
+	Removed []ScopeChange `json:"removed" yaml:"removed"`
+}
+
+// BurndownDay is the sprint scope, completed and remaining work at Date
+type BurndownDay struct {
+	Date            time.Time `json:"date" yaml:"date"`
+	ScopeIssues     int       `json:"scopeIssues" yaml:"scopeIssues"`
+	ScopePoints     float64   `json:"scopePoints" yaml:"scopePoints"`
+	DoneIssues      int       `json:"doneIssues" yaml:"doneIssues"`
+	DonePoints      float64   `json:"donePoints" yaml:"donePoints"`
+	RemainingIssues int       `json:"remainingIssues" yaml:"remainingIssues"`
+	RemainingPoints float64   `json:"remainingPoints" yaml:"remainingPoints"`
+	// Ideal is the remaining work, in Unit, if work was completed at a constant pace
+	Ideal float64 `json:"ideal" yaml:"ideal"`
+}
+
+// ScopeChange is an issue added to or removed from a sprint at Date
+type ScopeChange struct {
+	Key    string    `json:"key" yaml:"key"`
+	Date   time.Time `json:"date" yaml:"date"`
+	Points float64   `json:"points" yaml:"points"`
+}
+
+// ParseBurndownUnit returns the BurndownUnit with name unit
+func ParseBurndownUnit(unit string) (BurndownUnit, error) {
+	switch u := BurndownUnit(strings.ToLower(unit)); u {
+	case UnitPoints, UnitIssues:
+		return u, nil
+	}
+	return "", fmt.Errorf("unknown unit %q (supported: %s, %s)", unit, UnitPoints, UnitIssues)
+}
+
+// ParseDoneStatuses parses a comma separated list of status names, e.g. "Resolved,Closed"
+func ParseDoneStatuses(value string) []string {
+	statuses := make([]string, 0)
+	for _, status := range strings.Split(value, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// isDoneStatus returns true if status is one of doneStatuses (DefaultDoneStatuses if empty), ignoring case
+func isDoneStatus(status string, doneStatuses []string) bool {
+	if len(doneStatuses) == 0 {
+		doneStatuses = DefaultDoneStatuses
+	}
+	for _, done := range doneStatuses {
+		if strings.EqualFold(status, done) {
+			return true
+		}
+	}
+	return false
+}
+
+// withDefaults returns a copy of options, with defaults for the options not set
+func (o *BurndownOptions) withDefaults() BurndownOptions {
+	options := BurndownOptions{}
+	if o != nil {
+		options = *o
+	}
+	if options.Unit == "" {
+		options.Unit = UnitPoints
+	}
+	if len(options.DoneStatuses) == 0 {
+		options.DoneStatuses = DefaultDoneStatuses
+	}
+	return options
+}
+
+// sprintIssue is an issue of a sprint with the history of the fields needed by sprint reports
+type sprintIssue struct {
+	key      string
+	summary  string
+	status   string
+	assignee string
+	sprints  []fieldInterval
+	statuses []fieldInterval
+	points   []fieldInterval
+	// removed is true if the issue was found only by the project search: it is no longer in the sprint
+	removed bool
+}
+
+// valueAt returns the value in intervals at time t, false if the issue did not exist yet
+func valueAt(intervals []fieldInterval, t time.Time) (string, bool) {
+	if len(intervals) == 0 || t.Before(intervals[0].Start) {
+		return "", false
+	}
+	for i := range intervals {
+		if t.Before(intervals[i].End) {
+			return intervals[i].Value, true
+		}
+	}
+	return intervals[len(intervals)-1].Value, true
+}
+
+// inSprint returns true if sprints, the value of the Sprint field (comma separated
+// sprint names), contains sprintName
+func inSprint(sprints, sprintName string) bool {
+	for _, name := range strings.Split(sprints, ",") {
+		if strings.TrimSpace(name) == sprintName {
+			return true
+		}
+	}
+	return false
+}
+
+// everInSprint returns true if any of sprints, the history of the Sprint field, contains sprintName
+func everInSprint(sprints []fieldInterval, sprintName string) bool {
+	for i := range sprints {
+		if inSprint(sprints[i].Value, sprintName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *sprintIssue) memberAt(sprintName string, t time.Time) bool {
+	sprints, ok := valueAt(i.sprints, t)
+	return ok && inSprint(sprints, sprintName)
+}
+
+func (i *sprintIssue) doneAt(doneStatuses []string, t time.Time) bool {
+	status, _ := valueAt(i.statuses, t)
+	return isDoneStatus(status, doneStatuses)
+}
+
+func (i *sprintIssue) pointsAt(t time.Time) float64 {
+	value, _ := valueAt(i.points, t)
+	points, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return points
+}
+
+// sprintIssueSearch finds the issues which were in sprints, with the history of their sprints, status
+// and story points. The issues of the project updated since the earliest sprint started (candidates
+// for having been removed from a sprint) are searched once and shared by all sprints.
+type sprintIssueSearch struct {
+	jiraClient   JiraAPI
+	queryOptions *QueryOptions
+	// pointsColumn is nil if the story points field does not exist
+	pointsColumn *Column
+	// updated are the issues of the project updated since the earliest sprint started
+	updated []jira.Issue
+	logger  logr.Logger
+}
+
+// newSprintIssueSearch returns a sprintIssueSearch for sprints. If project is set, its issues
+// updated since the earliest of sprints started are searched.
+func newSprintIssueSearch(ctx context.Context, jiraClient JiraAPI, sprints []jira.Sprint, project string,
+	logger logr.Logger) (*sprintIssueSearch, error) {
+	fields := []string{"summary", "status", "assignee", "created"}
+	search := &sprintIssueSearch{jiraClient: jiraClient, logger: logger}
+	if columns, err := ResolveColumns(ctx, jiraClient, []string{"storyPoints"}, logger); err == nil {
+		search.pointsColumn = &columns[0]
+		fields = append(fields, search.pointsColumn.FieldID)
+	} else {
+		logger.Info(fmt.Sprintf("Story points not available: %v", err))
+	}
+	search.queryOptions = &QueryOptions{Expand: "changelog", Fields: fields}
+
+	var earliest *time.Time
+	for i := range sprints {
+		if sprints[i].StartDate != nil && (earliest == nil || sprints[i].StartDate.Before(*earliest)) {
+			earliest = sprints[i].StartDate
+		}
+	}
+	if project == "" || earliest == nil {
+		return search, nil
+	}
+
+	// removing an issue from a sprint updates it. JQL dates are in the user time zone:
+	// starting the day before covers any of them.
+	jql := fmt.Sprintf("project = %q AND updated >= %q", project, earliest.AddDate(0, 0, -1).Format("2006-01-02"))
+	updated, _, err := GetJiraIssues(ctx, jiraClient, jql, search.queryOptions, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetchChangelogs(ctx, jiraClient, updated, func(*jira.Issue) bool { return true }, logger); err != nil {
+		return nil, err
+	}
+	search.updated = updated
+	return search, nil
+}
+
+// hasPoints returns false if the story points field does not exist
+func (s *sprintIssueSearch) hasPoints() bool {
+	return s.pointsColumn != nil
+}
+
+// issues returns all issues which were in sprint: the issues in the sprint now and the
+// project issues updated whose Sprint field history contains it (issues removed from the sprint)
+func (s *sprintIssueSearch) issues(ctx context.Context, sprint *jira.Sprint) ([]sprintIssue, error) {
+	issues, _, err := GetJiraIssues(ctx, s.jiraClient, fmt.Sprintf("sprint = %d", sprint.ID), s.queryOptions, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetchChangelogs(ctx, s.jiraClient, issues, func(*jira.Issue) bool { return true }, s.logger); err != nil {
+		return nil, err
+	}
+	// issues after members are candidates for having been removed from the sprint
+	members := len(issues)
+	found := make(map[string]bool, len(issues))
+	for i := range issues {
+		found[issues[i].Key] = true
+	}
+	for i := range s.updated {
+		if !found[s.updated[i].Key] {
+			issues = append(issues, s.updated[i])
+		}
+	}
+
+	now := time.Now()
+	result := make([]sprintIssue, 0, len(issues))
+	for i := range issues {
+		issue := &issues[i]
+		// issues matching the sprint query are in the sprint now
+		current := ""
+		if i < members {
+			current = sprint.Name
+		}
+		sprints := fieldHistory(issue, sprintField, current, now)
+		if i >= members && !everInSprint(sprints, sprint.Name) {
+			continue
+		}
+		summary, status, assignee, points := "", "", unassigned, ""
+		if issue.Fields != nil {
+			summary = issue.Fields.Summary
+			if issue.Fields.Status != nil {
+				status = issue.Fields.Status.Name
+			}
+			assignee = assigneeName(issue.Fields.Assignee)
+		}
+		if s.pointsColumn != nil {
+			points = s.pointsColumn.Value(issue)
+		}
+		result = append(result, sprintIssue{
+			key:      issue.Key,
+			summary:  summary,
+			status:   status,
+			assignee: assignee,
+			sprints:  sprints,
+			statuses: fieldHistory(issue, statusField, status, now),
+			points:   fieldHistory(issue, storyPointsField, points, now),
+			removed:  i >= members,
+		})
+	}
+	return result, nil
+}
+
+// getSprintIssues returns all issues which were in sprint, with the history of their sprints, status
+// and story points: the issues in the sprint now and, if project is set, the issues of project updated
+// since the sprint started whose Sprint field history contains it (issues removed from the sprint).
+// Points are not available (false) if the story points field does not exist.
+func getSprintIssues(ctx context.Context, jiraClient JiraAPI, sprint *jira.Sprint, project string,
+	logger logr.Logger) ([]sprintIssue, bool, error) {
+	search, err := newSprintIssueSearch(ctx, jiraClient, []jira.Sprint{*sprint}, project, logger)
+	if err != nil {
+		return nil, false, err
+	}
+	issues, err := search.issues(ctx, sprint)
+	if err != nil {
+		return nil, false, err
+	}
+	return issues, search.hasPoints(), nil
+}
+
+// getSprint returns sprint sprintName of board boardID, the active sprint if sprintName is empty
+func getSprint(ctx context.Context, jiraClient JiraAPI, boardID, sprintName string, logger logr.Logger) (*jira.Sprint, error) {
+	if sprintName == "" {
+		return GetJiraActiveSprint(ctx, jiraClient, boardID, logger)
+	}
+	return GetJiraSprint(ctx, jiraClient, boardID, sprintName, logger)
+}
+
+// sprintPeriod returns when sprint started and ended (or ends). Returns an error if sprint has not started.
+func sprintPeriod(sprint *jira.Sprint) (time.Time, time.Time, error) {
+	if sprint.StartDate == nil || sprint.EndDate == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("sprint %s has not started", sprint.Name)
+	}
+	end := *sprint.EndDate
+	if sprint.CompleteDate != nil {
+		end = *sprint.CompleteDate
+	}
+	return *sprint.StartDate, end, nil
+}
+
+// GetBurndown reconstructs, from the issue changelogs, the scope, completed and remaining work of
+// sprint sprintName of board boardID (the active sprint if sprintName is empty) at its start and at
+// the end of each day, until its end or now. Issues added or removed after the start are accounted
+// for. Issues removed from the sprint are only found if options Project is set (see BurndownOptions).
+func GetBurndown(ctx context.Context, jiraClient JiraAPI, boardID, sprintName string, options *BurndownOptions,
+	logger logr.Logger) (*Burndown, error) {
+	burndownOptions := options.withDefaults()
+
+	sprint, err := getSprint(ctx, jiraClient, boardID, sprintName, logger)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := sprintPeriod(sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, hasPoints, err := getSprintIssues(ctx, jiraClient, sprint, burndownOptions.Project, logger)
+	if err != nil {
+		return nil, err
+	}
+	if burndownOptions.Unit == UnitPoints && !hasPoints {
+		logger.Info("Story points field not found: measuring work in issues")
+		burndownOptions.Unit = UnitIssues
+	}
+
+	burndown := &Burndown{Sprint: sprint.Name, State: sprint.State, StartDate: start, EndDate: *sprint.EndDate,
+		Unit: burndownOptions.Unit, Days: make([]BurndownDay, 0), Added: make([]ScopeChange, 0),
+		Removed: make([]ScopeChange, 0)}
+
+	last := end
+	if now := time.Now(); now.Before(last) {
+		last = now
+	}
+	for t := start; ; t = t.Add(24 * time.Hour) {
+		if t.After(last) {
+			t = last
+			// a sprint closed a few hours after the daily point ends on that point
+			if n := len(burndown.Days); n > 1 && last.Sub(burndown.Days[n-1].Date) < 12*time.Hour {
+				burndown.Days = burndown.Days[:n-1]
+			}
+		}
+		burndown.Days = append(burndown.Days, burndownDay(issues, sprint.Name, burndownOptions.DoneStatuses, t))
+		if !t.Before(last) {
+			break
+		}
+	}
+
+	initial := burndown.Days[0].ScopePoints
+	if burndown.Unit == UnitIssues {
+		initial = float64(burndown.Days[0].ScopeIssues)
+	}
+	for i := range burndown.Days {
+		// a sprint completed when (or before) it started has no span: its ideal line is already at 0
+		elapsed := 1.0
+		if span := end.Sub(start); span > 0 {
+			elapsed = burndown.Days[i].Date.Sub(start).Hours() / span.Hours()
+		}
+		burndown.Days[i].Ideal = roundDays(math.Max(0, initial*(1-elapsed)))
+	}
+
+	for i := range issues {
+		burndown.Added, burndown.Removed = appendScopeChanges(burndown.Added, burndown.Removed,
+			&issues[i], sprint.Name, start, last)
+	}
+	return burndown, nil
+}
+
+// burndownDay returns scope, done and remaining work at t
+func burndownDay(issues []sprintIssue, sprintName string, doneStatuses []string, t time.Time) BurndownDay {
+	day := BurndownDay{Date: t}
+	for i := range issues {
+		if !issues[i].memberAt(sprintName, t) {
+			continue
+		}
+		points := issues[i].pointsAt(t)
+		day.ScopeIssues++
+		day.ScopePoints += points
+		if issues[i].doneAt(doneStatuses, t) {
+			day.DoneIssues++
+			day.DonePoints += points
+		}
+	}
+	day.RemainingIssues = day.ScopeIssues - day.DoneIssues
+	day.RemainingPoints = day.ScopePoints - day.DonePoints
+	return day
+}
+
+// appendScopeChanges appends issue to added if it joined the sprint between start and last,
+// to removed if it left it
+func appendScopeChanges(added, removed []ScopeChange, issue *sprintIssue, sprintName string,
+	start, last time.Time) ([]ScopeChange, []ScopeChange) {
+	member := issue.memberAt(sprintName, start)
+	for i := range issue.sprints {
+		interval := &issue.sprints[i]
+		if !interval.Start.After(start) || interval.Start.After(last) {
+			continue
+		}
+		isMember := inSprint(interval.Value, sprintName)
+		change := ScopeChange{Key: issue.key, Date: interval.Start, Points: issue.pointsAt(interval.Start)}
+		if isMember && !member {
+			added = append(added, change)
+		} else if !isMember && member {
+			removed = append(removed, change)
+		}
+		member = isMember
+	}
+	return added, removed
+}
+
+// DisplayBurndown displays the burndown of sprint sprintName of board boardID (the active sprint if
+// sprintName is empty) as an ASCII chart and a table, or as a burnup chart if burnup is set.
+// If svgPath is set, a standalone SVG chart is also written to that file, once the burndown is built.
+func DisplayBurndown(ctx context.Context, jiraClient JiraAPI, boardID, sprintName string, burnup bool,
+	burndownOptions *BurndownOptions, options *DisplayOptions, svgPath string, logger logr.Logger) error {
+	if options == nil {
+		options = &DisplayOptions{}
+	}
+
+	burndown, err := GetBurndown(ctx, jiraClient, boardID, sprintName, burndownOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	if svgPath != "" {
+		if err := writeBurndownSVGFile(svgPath, burndown, burnup); err != nil {
+			return err
+		}
+	}
+
+	if options.Template != nil {
+		return options.Template.Execute(options.writer(), burndown)
+	}
+	return writeBurndown(options.writer(), options.Output, burndown, burnup)
+}
+
+// burndownHeaders are the csv/tsv headers of a BurndownDay
+var burndownHeaders = []string{"date", "scopeIssues", "scopePoints", "doneIssues", "donePoints",
+	"remainingIssues", "remainingPoints", "ideal"}
+
+func (d *BurndownDay) values() []string {
+	return []string{formatTime(&d.Date), fmt.Sprintf("%d", d.ScopeIssues), formatNumber(d.ScopePoints),
+		fmt.Sprintf("%d", d.DoneIssues), formatNumber(d.DonePoints), fmt.Sprintf("%d", d.RemainingIssues),
+		formatNumber(d.RemainingPoints), formatNumber(d.Ideal)}
+}
+
+// scope, done and remaining return the work at day d in unit
+func (d *BurndownDay) scope(unit BurndownUnit) float64 {
+	if unit == UnitIssues {
+		return float64(d.ScopeIssues)
+	}
+	return d.ScopePoints
+}
+
+func (d *BurndownDay) done(unit BurndownUnit) float64 {
+	if unit == UnitIssues {
+		return float64(d.DoneIssues)
+	}
+	return d.DonePoints
+}
+
+func (d *BurndownDay) remaining(unit BurndownUnit) float64 {
+	if unit == UnitIssues {
+		return float64(d.RemainingIssues)
+	}
+	return d.RemainingPoints
+}
+
+// writeBurndown writes burndown in the passed format
+func writeBurndown(w io.Writer, format OutputFormat, burndown *Burndown, burnup bool) error {
+	rows := make([][]string, len(burndown.Days))
+	for i := range burndown.Days {
+		rows[i] = burndown.Days[i].values()
+	}
+
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, burndown)
+	case OutputCSV, OutputTSV:
+		return writeSeparatedValues(w, format, burndownHeaders, rows)
+	}
+
+	headers := []string{"DATE", "SCOPE", "DONE", "REMAINING", "IDEAL"}
+	unit := burndown.Unit
+	rows = make([][]string, len(burndown.Days))
+	for i := range burndown.Days {
+		day := &burndown.Days[i]
+		rows[i] = []string{formatDate(day.Date), formatNumber(day.scope(unit)), formatNumber(day.done(unit)),
+			formatNumber(day.remaining(unit)), formatNumber(day.Ideal)}
+	}
+	caption := fmt.Sprintf("Sprint %s (%s), %s to %s, in %s. %s",
+		burndown.Sprint, burndown.State, formatDate(burndown.StartDate), formatDate(burndown.EndDate), unit,
+		formatScopeChanges(burndown))
+
+	if format == OutputMarkdown {
+		return writeMarkdown(w, headers, rows, caption)
+	}
+
+	if err := writeBurndownChart(w, burndown, burnup); err != nil {
+		return err
+	}
+	table := newTable(w, headers)
+	table.AppendBulk(rows)
+	table.Render()
+	_, err := fmt.Fprintln(w, caption)
+	return err
+}
+
+// formatScopeChanges describes the issues added to and removed from the sprint after its start
+func formatScopeChanges(burndown *Burndown) string {
+	describe := func(changes []ScopeChange) string {
+		if len(changes) == 0 {
+			return "none"
+		}
+		items := make([]string, len(changes))
+		for i := range changes {
+			items[i] = fmt.Sprintf("%s on %s", changes[i].Key, formatDate(changes[i].Date))
+		}
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("Added after start: %s. Removed: %s.", describe(burndown.Added), describe(burndown.Removed))
+}
+
+// burndownSeries returns the values charted for burndown: remaining and ideal work for
+// a burndown chart, completed work and scope for a burnup chart
+func burndownSeries(burndown *Burndown, burnup bool) (bars, line []float64) {
+	bars = make([]float64, len(burndown.Days))
+	line = make([]float64, len(burndown.Days))
+	for i := range burndown.Days {
+		day := &burndown.Days[i]
+		if burnup {
+			bars[i], line[i] = day.done(burndown.Unit), day.scope(burndown.Unit)
+		} else {
+			bars[i], line[i] = day.remaining(burndown.Unit), day.Ideal
+		}
+	}
+	return bars, line
+}
+
+// chartMax returns the largest of values, 1 if all are 0
+func chartMax(values ...[]float64) float64 {
+	max := 0.0
+	for _, v := range values {
+		for _, f := range v {
+			max = math.Max(max, f)
+		}
+	}
+	if max == 0 {
+		return 1
+	}
+	return max
+}
+
+// writeBurndownChart writes an ASCII chart of burndown: a column per day, # for remaining
+// (completed for burnup) work and . for the ideal line (- for the scope for burnup)
+func writeBurndownChart(w io.Writer, burndown *Burndown, burnup bool) error {
+	bars, line := burndownSeries(burndown, burnup)
+	max := chartMax(bars, line)
+	marker, legend := ".", fmt.Sprintf("# remaining %s  . ideal", burndown.Unit)
+	if burnup {
+		marker, legend = "-", fmt.Sprintf("# completed %s  - scope", burndown.Unit)
+	}
+
+	var sb strings.Builder
+	for row := chartHeight; row >= 1; row-- {
+		label := ""
+		if row == chartHeight || row == chartHeight/2 {
+			label = formatNumber(math.Round(max*float64(row)/chartHeight*10) / 10)
+		}
+		fmt.Fprintf(&sb, "%7s |", label)
+		for i := range bars {
+			cell := "  "
+			if math.Round(bars[i]/max*chartHeight) >= float64(row) {
+				cell = "##"
+			}
+			if math.Round(line[i]/max*chartHeight) == float64(row) {
+				cell = cell[:1] + marker
+			}
+			sb.WriteString(cell + " ")
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "%7s +%s\n", "0", strings.Repeat("---", len(bars)))
+	fmt.Fprintf(&sb, "%7s  ", "day")
+	for i := range bars {
+		fmt.Fprintf(&sb, "%-3d", i)
+	}
+	fmt.Fprintf(&sb, "\n%7s  %s\n\n", "", legend)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeBurndownSVGFile writes a standalone SVG chart of burndown to file path
+func writeBurndownSVGFile(path string, burndown *Burndown, burnup bool) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeBurndownSVG(f, burndown, burnup); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeBurndownSVG writes a standalone SVG chart of burndown
+func writeBurndownSVG(w io.Writer, burndown *Burndown, burnup bool) error {
+	const width, height, margin = 800.0, 400.0, 50.0
+	bars, line := burndownSeries(burndown, burnup)
+	max := chartMax(bars, line)
+	title, barsName, lineName := "Burndown", "remaining", "ideal"
+	if burnup {
+		title, barsName, lineName = "Burnup", "completed", "scope"
+	}
+
+	step := (width - 2*margin) / math.Max(1, float64(len(bars)-1))
+	x := func(i int) float64 { return margin + float64(i)*step }
+	y := func(v float64) float64 { return height - margin - v/max*(height-2*margin) }
+	points := func(values []float64) string {
+		p := make([]string, len(values))
+		for i := range values {
+			p[i] = fmt.Sprintf("%.1f,%.1f", x(i), y(values[i]))
+		}
+		return strings.Join(p, " ")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif" font-size="12">`+"\n",
+		width, height)
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(&sb, `<text x="%.0f" y="25" font-size="16">%s %s (%s to %s, %s)</text>`+"\n", margin,
+		title, escapeXML(burndown.Sprint), formatDate(burndown.StartDate), formatDate(burndown.EndDate), burndown.Unit)
+	fmt.Fprintf(&sb, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="black"/>`+"\n",
+		margin, height-margin, width-margin, height-margin)
+	fmt.Fprintf(&sb, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="black"/>`+"\n",
+		margin, margin, margin, height-margin)
+	for _, v := range []float64{0, max / 2, max} {
+		fmt.Fprintf(&sb, `<text x="%.0f" y="%.1f" text-anchor="end">%s</text>`+"\n",
+			margin-5, y(v)+4, formatNumber(math.Round(v*10)/10))
+	}
+	for i := range burndown.Days {
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.0f" text-anchor="middle">%s</text>`+"\n",
+			x(i), height-margin+18, burndown.Days[i].Date.Format("01-02"))
+	}
+	fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="gray" stroke-dasharray="6,4" stroke-width="2"/>`+"\n",
+		points(line))
+	fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="crimson" stroke-width="3"/>`+"\n", points(bars))
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" fill="crimson">%s</text>`+"\n", width-margin-150, margin, barsName)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" fill="gray">%s</text>`+"\n", width-margin-150, margin+16, lineName)
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// escapeXML escapes s for use in XML text
+func escapeXML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
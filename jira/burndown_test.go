@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// intervals returns the field history with value values[i] from times[i] to times[i+1] (end the last)
+func intervals(end time.Time, times []time.Time, values ...string) []fieldInterval {
+	result := make([]fieldInterval, len(values))
+	for i := range values {
+		result[i] = fieldInterval{Value: values[i], Start: times[i], End: end}
+		if i+1 < len(times) {
+			result[i].End = times[i+1]
+		}
+	}
+	return result
+}
+
+func TestBurndownDay(t *testing.T) {
+	start := time.Date(2022, 4, 18, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	end := day(14)
+	created := day(-10)
+
+	issues := []sprintIssue{
+		// in the sprint from the start, done on day 3
+		{key: "A-1", sprints: intervals(end, []time.Time{created}, "Sprint-42"),
+			statuses: intervals(end, []time.Time{created, day(3)}, "In Progress", "Resolved"),
+			points:   intervals(end, []time.Time{created}, "3")},
+		// added on day 2, points raised on day 4
+		{key: "A-2", sprints: intervals(end, []time.Time{created, day(2)}, "", "Sprint-41, Sprint-42"),
+			statuses: intervals(end, []time.Time{created}, "Backlog"),
+			points:   intervals(end, []time.Time{created, day(4)}, "1", "2")},
+		// removed on day 1
+		{key: "A-3", sprints: intervals(end, []time.Time{created, day(1)}, "Sprint-42", ""),
+			statuses: intervals(end, []time.Time{created}, "Closed"),
+			points:   intervals(end, []time.Time{created}, "5")},
+		// created on day 5, no points
+		{key: "A-4", sprints: intervals(end, []time.Time{day(5)}, "Sprint-42"),
+			statuses: intervals(end, []time.Time{day(5)}, "done"),
+			points:   intervals(end, []time.Time{day(5)}, "")},
+	}
+
+	tests := []struct {
+		at   time.Time
+		want BurndownDay
+	}{
+		{day(-11), BurndownDay{}},
+		{day(0), BurndownDay{ScopeIssues: 2, ScopePoints: 8, DoneIssues: 1, DonePoints: 5,
+			RemainingIssues: 1, RemainingPoints: 3}},
+		{day(1), BurndownDay{ScopeIssues: 1, ScopePoints: 3, RemainingIssues: 1, RemainingPoints: 3}},
+		{day(2), BurndownDay{ScopeIssues: 2, ScopePoints: 4, RemainingIssues: 2, RemainingPoints: 4}},
+		{day(3), BurndownDay{ScopeIssues: 2, ScopePoints: 4, DoneIssues: 1, DonePoints: 3,
+			RemainingIssues: 1, RemainingPoints: 1}},
+		{day(5), BurndownDay{ScopeIssues: 3, ScopePoints: 5, DoneIssues: 2, DonePoints: 3,
+			RemainingIssues: 1, RemainingPoints: 2}},
+	}
+
+	for _, tt := range tests {
+		tt.want.Date = tt.at
+		got := burndownDay(issues, "Sprint-42", DefaultDoneStatuses, tt.at)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("burndownDay at %s = %+v, want %+v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestAppendScopeChanges(t *testing.T) {
+	start := time.Date(2022, 4, 18, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	end := day(14)
+	created := day(-10)
+	points := intervals(end, []time.Time{created, day(3)}, "1", "2")
+
+	tests := []struct {
+		name        string
+		sprints     []fieldInterval
+		last        time.Time
+		wantAdded   []ScopeChange
+		wantRemoved []ScopeChange
+	}{
+		{name: "in the sprint from the start", sprints: intervals(end, []time.Time{created}, "Sprint-42"), last: end},
+		{name: "added at the start", sprints: intervals(end, []time.Time{created, start}, "", "Sprint-42"), last: end},
+		{name: "added", sprints: intervals(end, []time.Time{created, day(4)}, "", "Sprint-41, Sprint-42"), last: end,
+			wantAdded: []ScopeChange{{Key: "A-1", Date: day(4), Points: 2}}},
+		{name: "removed", sprints: intervals(end, []time.Time{created, day(2)}, "Sprint-42", "Sprint-43"), last: end,
+			wantRemoved: []ScopeChange{{Key: "A-1", Date: day(2), Points: 1}}},
+		{name: "added and removed",
+			sprints: intervals(end, []time.Time{created, day(1), day(5)}, "", "Sprint-42", ""), last: end,
+			wantAdded:   []ScopeChange{{Key: "A-1", Date: day(1), Points: 1}},
+			wantRemoved: []ScopeChange{{Key: "A-1", Date: day(5), Points: 2}}},
+		{name: "changes after last are ignored",
+			sprints: intervals(end, []time.Time{created, day(1), day(5)}, "", "Sprint-42", ""), last: day(3),
+			wantAdded: []ScopeChange{{Key: "A-1", Date: day(1), Points: 1}}},
+		{name: "other sprints", sprints: intervals(end, []time.Time{created, day(1)}, "Sprint-41", "Sprint-420"), last: end},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &sprintIssue{key: "A-1", sprints: tt.sprints, points: points}
+			added, removed := appendScopeChanges(nil, nil, issue, "Sprint-42", start, tt.last)
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("got added %+v, want %+v", added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("got removed %+v, want %+v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package jira
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// CacheResource is a kind of Jira resource kept in the response cache
+type CacheResource string
+
+const (
+	// CacheProject is a project (GET rest/api/2/project/{key})
+	CacheProject = CacheResource("project")
+	// CacheBoards is a page of the board list (GET rest/agile/1.0/board)
+	CacheBoards = CacheResource("boards")
+	// CacheSprints is the sprint list of a board (GET rest/agile/1.0/board/{id}/sprint)
+	CacheSprints = CacheResource("sprints")
+)
+
+// defaultCacheTTLs is how long each resource is used without asking Jira, when cacheTTL does not set it.
+// Sprints change state (future, active, closed) more often than projects and boards.
+var defaultCacheTTLs = map[CacheResource]time.Duration{
+	CacheProject: 24 * time.Hour,
+	CacheBoards:  time.Hour,
+	CacheSprints: 10 * time.Minute,
+}
+
+// cacheResourcePaths identifies the cached resource from the request path
+var cacheResourcePaths = map[CacheResource]*regexp.Regexp{
+	CacheProject: regexp.MustCompile(`/rest/api/[23]/project/[^/]+$`),
+	CacheBoards:  regexp.MustCompile(`/rest/agile/1\.0/board$`),
+	CacheSprints: regexp.MustCompile(`/rest/agile/1\.0/board/[0-9]+/sprint$`),
+}
+
+// cacheDisabled is set by DisableCache
+var cacheDisabled bool
+
+// DisableCache makes clients returned by GetJiraClient fetch every resource
+// from Jira, ignoring cached responses. Responses are still stored in the cache.
+func DisableCache() {
+	cacheDisabled = true
+}
+
+// ResponseCache is the on-disk cache of Jira responses for one base URL and profile.
+// Only projects, boards and sprints are cached: each of them is used for the TTL of
+// its CacheResource, then revalidated with If-None-Match/If-Modified-Since when
+// Jira sent an ETag or Last-Modified header, fetched again otherwise.
+type ResponseCache struct {
+	// Dir is the directory with the cached responses
+	Dir  string
+	TTLs map[CacheResource]time.Duration
+}
+
+// cacheEntry is a cached response, stored as JSON in a file of ResponseCache Dir
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	StoredAt     time.Time   `json:"storedAt"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// CacheRoot returns the directory with the response caches of all base URLs
+// and profiles: jira_utils in the user cache directory (e.g. ~/.cache/jira_utils)
+func CacheRoot() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "jira_utils"), nil
+}
+
+// GetResponseCache returns the cache of Jira at baseURL for the profile in use,
+// with the TTLs set with setting cacheTTL
+func GetResponseCache(baseURL string) (*ResponseCache, error) {
+	ttls, err := GetCacheTTLs()
+	if err != nil {
+		return nil, err
+	}
+	root, err := CacheRoot()
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseCache{Dir: filepath.Join(root, hashKey(strings.TrimSuffix(baseURL, "/")+"\n"+ActiveProfile())),
+		TTLs: ttls}, nil
+}
+
+// GetCacheTTLs returns the TTL of each CacheResource: defaults overridden by setting
+// cacheTTL, a comma separated list of <resource>=<duration> (e.g. sprints=5m,boards=0).
+// A TTL of 0 disables caching of the resource.
+func GetCacheTTLs() (map[CacheResource]time.Duration, error) {
+	ttls := make(map[CacheResource]time.Duration, len(defaultCacheTTLs))
+	for resource, ttl := range defaultCacheTTLs {
+		ttls[resource] = ttl
+	}
+
+	value, source := LookupSetting(CacheTTLSetting)
+	if value == "" {
+		return ttls, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		resource := CacheResource(strings.TrimSpace(parts[0]))
+		if _, ok := defaultCacheTTLs[resource]; !ok || len(parts) != 2 {
+			return nil, fmt.Errorf("%s %q (from %s): %q must be <resource>=<duration> with resource one of %s, %s, %s",
+				CacheTTLSetting, value, source, item, CacheProject, CacheBoards, CacheSprints)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("%s %q (from %s): TTL of %s must be a duration, e.g. 30m or 24h",
+				CacheTTLSetting, value, source, resource)
+		}
+		ttls[resource] = ttl
+	}
+	return ttls, nil
+}
+
+// Clear removes all cached responses
+func (c *ResponseCache) Clear() error {
+	return os.RemoveAll(c.Dir)
+}
+
+// ClearCaches removes the cached responses of all base URLs and profiles
+func ClearCaches() error {
+	root, err := CacheRoot()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(root)
+}
+
+// resource returns the CacheResource requested by req, if cached
+func (c *ResponseCache) resource(req *http.Request) (CacheResource, bool) {
+	if req.Method != http.MethodGet {
+		return "", false
+	}
+	for resource, path := range cacheResourcePaths {
+		if path.MatchString(req.URL.Path) {
+			return resource, c.TTLs[resource] > 0
+		}
+	}
+	return "", false
+}
+
+func (c *ResponseCache) entryPath(url string) string {
+	return filepath.Join(c.Dir, hashKey(url)+".json")
+}
+
+// get returns the cached response to url, nil if none
+func (c *ResponseCache) get(url string) *cacheEntry {
+	content, err := os.ReadFile(c.entryPath(url))
+	if err != nil {
+		return nil
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(content, entry); err != nil || entry.URL != url {
+		return nil
+	}
+	return entry
+}
+
+func (c *ResponseCache) put(entry *cacheEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(c.entryPath(entry.URL), content)
+}
+
+// hashKey returns a file name safe key for value
+func hashKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:16])
+}
+
+// cacheTransport answers requests for cached resources from cache.
+// Cache-Control sent by Jira is ignored: Jira marks most REST responses
+// no-cache, while projects, boards and sprints rarely change.
+type cacheTransport struct {
+	cache     *ResponseCache
+	transport http.RoundTripper
+	logger    logr.Logger
+}
+
+func newCacheTransport(cache *ResponseCache, transport http.RoundTripper, logger logr.Logger) *cacheTransport {
+	return &cacheTransport{cache: cache, transport: transport, logger: logger}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource, ok := t.cache.resource(req)
+	if !ok {
+		return t.transport.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	var entry *cacheEntry
+	if !cacheDisabled {
+		entry = t.cache.get(url)
+	}
+	if entry != nil {
+		age := time.Since(entry.StoredAt)
+		if age < t.cache.TTLs[resource] {
+			t.logger.V(1).Info(fmt.Sprintf("Using cached %s %s (age %s)", resource, req.URL.Redacted(), age.Round(time.Second)))
+			return entry.response(req), nil
+		}
+		if entry.ETag != "" || entry.LastModified != "" {
+			req = req.Clone(req.Context()) // per RoundTripper contract
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		t.logger.V(1).Info(fmt.Sprintf("Cached %s %s not modified", resource, req.URL.Redacted()))
+		entry.StoredAt = time.Now()
+		if err := t.cache.put(entry); err != nil {
+			t.logger.Info(fmt.Sprintf("Failed to update cache %s: %v", t.cache.Dir, err))
+		}
+		return entry.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry = &cacheEntry{URL: url, StoredAt: time.Now(), ETag: resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"), Body: body,
+		// other headers (e.g. Set-Cookie) are not needed to use the body
+		Header: http.Header{"Content-Type": resp.Header.Values("Content-Type")}}
+	if err := t.cache.put(entry); err != nil {
+		// cache is an optimization: a failure only costs a request next time
+		t.logger.Info(fmt.Sprintf("Failed to update cache %s: %v", t.cache.Dir, err))
+	}
+	return resp, nil
+}
+
+// response returns the cached response as answer to req
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
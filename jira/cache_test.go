@@ -0,0 +1,155 @@
+package jira
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestCacheTransport(t *testing.T) {
+	const sprintsURL = "https://jira.example.com/rest/agile/1.0/board/1/sprint"
+	const searchURL = "https://jira.example.com/rest/api/2/search"
+
+	tests := []struct {
+		name string
+		url  string
+		// stored, if set, is the cached response, stored age ago
+		stored *cacheEntry
+		age    time.Duration
+		// status, etag and body are sent by Jira
+		status int
+		etag   string
+		body   string
+
+		wantSent        int
+		wantIfNoneMatch string
+		wantBody        string
+		// wantCached is the body cached after the request, empty if none
+		wantCached string
+	}{
+		{
+			name:       "not cached resource",
+			url:        searchURL,
+			status:     http.StatusOK,
+			body:       "issues",
+			wantSent:   1,
+			wantBody:   "issues",
+			wantCached: "",
+		},
+		{
+			name:       "miss",
+			url:        sprintsURL,
+			status:     http.StatusOK,
+			etag:       `"v1"`,
+			body:       "sprints v1",
+			wantSent:   1,
+			wantBody:   "sprints v1",
+			wantCached: "sprints v1",
+		},
+		{
+			name:       "hit within TTL",
+			url:        sprintsURL,
+			stored:     &cacheEntry{ETag: `"v1"`, Body: []byte("sprints v1")},
+			age:        time.Minute,
+			wantSent:   0,
+			wantBody:   "sprints v1",
+			wantCached: "sprints v1",
+		},
+		{
+			name:            "expired and not modified",
+			url:             sprintsURL,
+			stored:          &cacheEntry{ETag: `"v1"`, Body: []byte("sprints v1")},
+			age:             time.Hour,
+			status:          http.StatusNotModified,
+			wantSent:        1,
+			wantIfNoneMatch: `"v1"`,
+			wantBody:        "sprints v1",
+			wantCached:      "sprints v1",
+		},
+		{
+			name:            "expired and modified",
+			url:             sprintsURL,
+			stored:          &cacheEntry{ETag: `"v1"`, Body: []byte("sprints v1")},
+			age:             time.Hour,
+			status:          http.StatusOK,
+			etag:            `"v2"`,
+			body:            "sprints v2",
+			wantSent:        1,
+			wantIfNoneMatch: `"v1"`,
+			wantBody:        "sprints v2",
+			wantCached:      "sprints v2",
+		},
+		{
+			name:       "errors are not cached",
+			url:        sprintsURL,
+			status:     http.StatusInternalServerError,
+			body:       "failure",
+			wantSent:   1,
+			wantBody:   "failure",
+			wantCached: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &ResponseCache{Dir: t.TempDir(), TTLs: map[CacheResource]time.Duration{CacheSprints: 10 * time.Minute}}
+			if tt.stored != nil {
+				tt.stored.URL = tt.url
+				tt.stored.StoredAt = time.Now().Add(-tt.age)
+				if err := cache.put(tt.stored); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sent := 0
+			ifNoneMatch := ""
+			server := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				sent++
+				ifNoneMatch = req.Header.Get("If-None-Match")
+				resp := response(tt.status, "ETag", tt.etag)
+				resp.Body = io.NopCloser(strings.NewReader(tt.body))
+				resp.Request = req
+				return resp, nil
+			})
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := newCacheTransport(cache, server, logr.Discard()).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if sent != tt.wantSent {
+				t.Errorf("sent %d requests to Jira, want %d", sent, tt.wantSent)
+			}
+			if ifNoneMatch != tt.wantIfNoneMatch {
+				t.Errorf("got If-None-Match %q, want %q", ifNoneMatch, tt.wantIfNoneMatch)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("got body %q, want %q", body, tt.wantBody)
+			}
+
+			cached := ""
+			if entry := cache.get(tt.url); entry != nil {
+				cached = string(entry.Body)
+				if tt.wantSent > 0 && time.Since(entry.StoredAt) > time.Minute {
+					t.Errorf("cached entry not refreshed: stored %s ago", time.Since(entry.StoredAt))
+				}
+			}
+			if cached != tt.wantCached {
+				t.Errorf("got cached body %q, want %q", cached, tt.wantCached)
+			}
+		})
+	}
+}
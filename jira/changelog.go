@@ -0,0 +1,178 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+const (
+	// statusField is the changelog field changed on status transitions
+	statusField = "status"
+	// assigneeField is the changelog field changed when the issue is (un)assigned
+	assigneeField = "assignee"
+	// changelogWorkers is the number of changelogs fetched concurrently by fetchChangelogs
+	changelogWorkers = 8
+)
+
+// addExpand returns expand (comma separated list as passed to Jira) with value added, if not there yet
+func addExpand(expand, value string) string {
+	if expand == "" {
+		return value
+	}
+	for _, v := range strings.Split(expand, ",") {
+		if strings.TrimSpace(v) == value {
+			return expand
+		}
+	}
+	return expand + "," + value
+}
+
+// fetchChangelogs fetches the changelog of the issues for which needed returns true,
+// and which do not have it yet (e.g. Jira ignored expand=changelog in the search).
+// Up to changelogWorkers issues are fetched at the same time. An issue whose changelog
+// cannot be fetched is left without it. Returns an error only if ctx is canceled.
+func fetchChangelogs(ctx context.Context, jiraClient JiraAPI, issues []jira.Issue,
+	needed func(issue *jira.Issue) bool, logger logr.Logger) error {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < changelogWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				issue, err := jiraClient.GetIssue(ctx, issues[i].ID, &jira.GetQueryOptions{Expand: "changelog"})
+				if err != nil {
+					logger.Info(fmt.Sprintf("Failed to get changelog of issue %s. Error: %v", issues[i].Key, err))
+					continue
+				}
+				issues[i].Changelog = issue.Changelog
+			}
+		}()
+	}
+
+	for i := range issues {
+		if issues[i].Changelog != nil || !needed(&issues[i]) {
+			continue
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// StatusSince returns when issue moved to its current status, looking at
+// the most recent status transition in its changelog.
+// If the changelog contains no status transition (or was not requested),
+// the issue creation time is returned.
+func StatusSince(issue *jira.Issue) time.Time {
+	var since time.Time
+	if issue.Fields == nil {
+		return since
+	}
+	since = time.Time(issue.Fields.Created)
+
+	if issue.Changelog == nil {
+		return since
+	}
+
+	var latest time.Time
+	for i := range issue.Changelog.Histories {
+		history := &issue.Changelog.Histories[i]
+		historyTime, err := history.CreatedTime()
+		if err != nil {
+			continue
+		}
+		for j := range history.Items {
+			if history.Items[j].Field == statusField && historyTime.After(latest) {
+				latest = historyTime
+			}
+		}
+	}
+
+	if !latest.IsZero() {
+		return latest
+	}
+	return since
+}
+
+// fieldInterval is a period during which a field of an issue had Value
+type fieldInterval struct {
+	Value string
+	Start time.Time
+	End   time.Time
+}
+
+// fieldChange is a change of a field in the issue changelog
+type fieldChange struct {
+	At   time.Time
+	From string
+	To   string
+}
+
+// fieldChanges returns the changes of field (case insensitive, e.g. status, assignee, Sprint)
+// in the issue changelog, in chronological order
+func fieldChanges(issue *jira.Issue, field string) []fieldChange {
+	changes := make([]fieldChange, 0)
+	if issue.Changelog == nil {
+		return changes
+	}
+	for i := range issue.Changelog.Histories {
+		history := &issue.Changelog.Histories[i]
+		historyTime, err := history.CreatedTime()
+		if err != nil {
+			continue
+		}
+		for j := range history.Items {
+			if strings.EqualFold(history.Items[j].Field, field) {
+				changes = append(changes, fieldChange{At: historyTime,
+					From: history.Items[j].FromString, To: history.Items[j].ToString})
+			}
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].At.Before(changes[j].At) })
+	return changes
+}
+
+// fieldHistory returns the values field had from issue creation until now, in chronological order,
+// rebuilt from the changelog. The value before the first change is the one the change was from;
+// current is the value of an issue whose field never changed.
+func fieldHistory(issue *jira.Issue, field, current string, now time.Time) []fieldInterval {
+	start := now
+	if issue.Fields != nil {
+		start = time.Time(issue.Fields.Created)
+	}
+
+	changes := fieldChanges(issue, field)
+	if len(changes) == 0 {
+		return []fieldInterval{{Value: current, Start: start, End: now}}
+	}
+
+	intervals := make([]fieldInterval, 0, len(changes)+1)
+	value := changes[0].From
+	for i := range changes {
+		if changes[i].At.After(start) {
+			intervals = append(intervals, fieldInterval{Value: value, Start: start, End: changes[i].At})
+			start = changes[i].At
+		}
+		value = changes[i].To
+	}
+	if now.After(start) {
+		intervals = append(intervals, fieldInterval{Value: value, Start: start, End: now})
+	}
+	return intervals
+}
@@ -0,0 +1,118 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// change returns a changelog history changing field from from to to at time at
+func change(at time.Time, field, from, to string) jira.ChangelogHistory {
+	return jira.ChangelogHistory{Created: at.Format(changelogTime),
+		Items: []jira.ChangelogItems{{Field: field, FromString: from, ToString: to}}}
+}
+
+func TestFieldHistory(t *testing.T) {
+	created := time.Date(2022, 4, 1, 10, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return created.AddDate(0, 0, n) }
+	now := day(10)
+
+	tests := []struct {
+		name      string
+		field     string
+		created   time.Time
+		histories []jira.ChangelogHistory
+		current   string
+		want      []fieldInterval
+	}{
+		{
+			name:    "never changed",
+			field:   statusField,
+			created: created,
+			current: "Backlog",
+			want:    []fieldInterval{{Value: "Backlog", Start: created, End: now}},
+		},
+		{
+			name:    "other fields are ignored",
+			field:   statusField,
+			created: created,
+			histories: []jira.ChangelogHistory{
+				change(day(2), assigneeField, "", "mgianluc"),
+			},
+			current: "Backlog",
+			want:    []fieldInterval{{Value: "Backlog", Start: created, End: now}},
+		},
+		{
+			name:    "changes in chronological order",
+			field:   statusField,
+			created: created,
+			histories: []jira.ChangelogHistory{
+				// Jira can return histories out of order
+				change(day(5), "Status", "In Progress", "Resolved"),
+				change(day(2), statusField, "Backlog", "In Progress"),
+			},
+			current: "Resolved",
+			want: []fieldInterval{
+				{Value: "Backlog", Start: created, End: day(2)},
+				{Value: "In Progress", Start: day(2), End: day(5)},
+				{Value: "Resolved", Start: day(5), End: now},
+			},
+		},
+		{
+			name:    "change at creation",
+			field:   sprintField,
+			created: created,
+			histories: []jira.ChangelogHistory{
+				change(created, sprintField, "", "Sprint-42"),
+				change(day(3), sprintField, "Sprint-42", ""),
+			},
+			current: "",
+			want: []fieldInterval{
+				{Value: "Sprint-42", Start: created, End: day(3)},
+				{Value: "", Start: day(3), End: now},
+			},
+		},
+		{
+			name:    "invalid history dates are skipped",
+			field:   statusField,
+			created: created,
+			histories: []jira.ChangelogHistory{
+				{Created: "yesterday", Items: []jira.ChangelogItems{{Field: statusField, FromString: "Backlog", ToString: "Closed"}}},
+				change(day(4), statusField, "Backlog", "In Progress"),
+			},
+			current: "In Progress",
+			want: []fieldInterval{
+				{Value: "Backlog", Start: created, End: day(4)},
+				{Value: "In Progress", Start: day(4), End: now},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &jira.Issue{Key: "TEST-1", Fields: &jira.IssueFields{Created: jira.Time(tt.created)},
+				Changelog: &jira.Changelog{Histories: tt.histories}}
+			got := fieldHistory(issue, tt.field, tt.current, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d intervals %+v, want %+v", len(got), got, tt.want)
+			}
+			for i := range got {
+				if got[i].Value != tt.want[i].Value || !got[i].Start.Equal(tt.want[i].Start) ||
+					!got[i].End.Equal(tt.want[i].End) {
+					t.Errorf("interval %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFieldHistoryWithoutChangelog(t *testing.T) {
+	now := time.Date(2022, 4, 29, 12, 0, 0, 0, time.UTC)
+	got := fieldHistory(&jira.Issue{Key: "TEST-1"}, statusField, "Backlog", now)
+	want := []fieldInterval{{Value: "Backlog", Start: now, End: now}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,297 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+// Column is a column displayed for each issue
+type Column struct {
+	// Name is the column name, as passed to ResolveColumns
+	Name string
+	// Header is the column header in table and markdown output
+	Header string
+	// FieldID is the ID of the Jira field needed by this column
+	FieldID string
+	// value returns the column value for issue
+	value func(issue *jira.Issue) string
+	// sortValue, if set, returns the value used to sort issues by this column
+	sortValue func(issue *jira.Issue) string
+}
+
+// Value returns the column value for issue
+func (c *Column) Value(issue *jira.Issue) string {
+	if issue.Fields == nil && c.FieldID != "key" {
+		return ""
+	}
+	return c.value(issue)
+}
+
+// SortValue returns the value used to sort issues by this column
+func (c *Column) SortValue(issue *jira.Issue) string {
+	if c.sortValue == nil || issue.Fields == nil {
+		return c.Value(issue)
+	}
+	return c.sortValue(issue)
+}
+
+// standardColumn describes a column available by name with --columns
+type standardColumn struct {
+	header  string
+	fieldID string
+	// customFieldName, if set, is the name of the custom field backing this
+	// column (its ID differs across Jira instances)
+	customFieldName string
+	value           func(issue *jira.Issue) string
+	sortValue       func(issue *jira.Issue) string
+}
+
+// standardColumns are the columns available by name, keyed by lowercase name
+var standardColumns = map[string]standardColumn{
+	"key":     {header: "KEY", fieldID: "key", value: func(i *jira.Issue) string { return i.Key }},
+	"summary": {header: "SUMMARY", fieldID: "summary", value: func(i *jira.Issue) string { return i.Fields.Summary }},
+	"status":  {header: "STATUS", fieldID: "status", value: statusName},
+	"updated": {header: "LAST UPDATE", fieldID: "updated", value: lastUpdate, sortValue: func(i *jira.Issue) string {
+		// most recently updated first, consistently with displayed value (days since last update)
+		return fmt.Sprintf("%d", -time.Time(i.Fields.Updated).Unix())
+	}},
+	"assignee": {header: "ASSIGNEE", fieldID: "assignee", value: func(i *jira.Issue) string { return userName(i.Fields.Assignee) }},
+	"reporter": {header: "REPORTER", fieldID: "reporter", value: func(i *jira.Issue) string { return userName(i.Fields.Reporter) }},
+	"priority": {header: "PRIORITY", fieldID: "priority", value: func(i *jira.Issue) string {
+		if i.Fields.Priority == nil {
+			return ""
+		}
+		return i.Fields.Priority.Name
+	}, sortValue: func(i *jira.Issue) string {
+		// priority IDs go from the highest (1) to the lowest priority
+		if i.Fields.Priority == nil {
+			return ""
+		}
+		return i.Fields.Priority.ID
+	}},
+	"type": {header: "TYPE", fieldID: "issuetype", value: func(i *jira.Issue) string { return i.Fields.Type.Name }},
+	"components": {header: "COMPONENTS", fieldID: "components", value: func(i *jira.Issue) string {
+		names := make([]string, 0)
+		for _, c := range i.Fields.Components {
+			names = append(names, c.Name)
+		}
+		return strings.Join(names, ", ")
+	}},
+	"labels": {header: "LABELS", fieldID: "labels", value: func(i *jira.Issue) string { return strings.Join(i.Fields.Labels, ", ") }},
+	"fixversions": {header: "FIX VERSIONS", fieldID: "fixVersions", value: func(i *jira.Issue) string {
+		names := make([]string, 0)
+		for _, v := range i.Fields.FixVersions {
+			names = append(names, v.Name)
+		}
+		return strings.Join(names, ", ")
+	}},
+	"created": {header: "CREATED", fieldID: "created", value: func(i *jira.Issue) string {
+		return formatDate(time.Time(i.Fields.Created))
+	}, sortValue: func(i *jira.Issue) string {
+		return fmt.Sprintf("%d", time.Time(i.Fields.Created).Unix())
+	}},
+	"duedate": {header: "DUE DATE", fieldID: "duedate", value: func(i *jira.Issue) string {
+		return formatDate(time.Time(i.Fields.Duedate))
+	}},
+	"storypoints": {header: "STORY POINTS", customFieldName: "Story Points"},
+	// Epic Link is a custom field when using the REST API, while the agile API
+	// returns field "epic". The latter is used if the former is not found.
+	"epiclink": {header: "EPIC LINK", fieldID: "epic", customFieldName: "Epic Link", value: func(i *jira.Issue) string {
+		if i.Fields.Epic == nil {
+			return ""
+		}
+		return i.Fields.Epic.Key
+	}},
+	// Sprint is a custom field when using the REST API, while the agile API
+	// returns it as field "sprint". The latter is used if the former is not found.
+	"sprint": {header: "SPRINT", fieldID: "sprint", customFieldName: "Sprint", value: func(i *jira.Issue) string {
+		if i.Fields.Sprint == nil {
+			return ""
+		}
+		return i.Fields.Sprint.Name
+	}},
+}
+
+// DefaultColumns are the columns displayed when none is specified
+var DefaultColumns = []string{"key", "summary", "status", "updated", "assignee"}
+
+// ResolveColumns returns the columns with passed names. A name can be any
+// of the standard columns (key, summary, status, updated, assignee, reporter,
+// priority, type, components, labels, fixVersions, created, dueDate,
+// storyPoints, epicLink, sprint), or the ID or name of a custom field.
+// Custom field names are resolved using the Jira field metadata, which is
+// fetched only if needed.
+func ResolveColumns(ctx context.Context, jiraClient JiraAPI, names []string, logger logr.Logger) ([]Column, error) {
+	return newColumnResolver(ctx, jiraClient, logger)(names)
+}
+
+// newColumnResolver returns a function resolving column names like ResolveColumns.
+// Jira field metadata is fetched at most once across all calls of the returned function.
+func newColumnResolver(ctx context.Context, jiraClient JiraAPI, logger logr.Logger) func([]string) ([]Column, error) {
+	var fields []jira.Field
+	getFields := func() ([]jira.Field, error) {
+		if fields != nil {
+			return fields, nil
+		}
+		var err error
+		fields, err = jiraClient.GetFields(ctx)
+		if err != nil {
+			logger.Info(fmt.Sprintf("Failed to get fields. Error: %v", err))
+			return nil, err
+		}
+		return fields, nil
+	}
+
+	return func(names []string) ([]Column, error) {
+		return resolveColumns(names, getFields)
+	}
+}
+
+// resolveColumns returns the columns with passed names, calling getFields for custom fields
+func resolveColumns(names []string, getFields func() ([]jira.Field, error)) ([]Column, error) {
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		standard, isStandard := standardColumns[strings.ToLower(strings.ReplaceAll(name, " ", ""))]
+		if isStandard && standard.customFieldName == "" {
+			columns = append(columns, Column{Name: name, Header: standard.header,
+				FieldID: standard.fieldID, value: standard.value, sortValue: standard.sortValue})
+			continue
+		}
+
+		allFields, err := getFields()
+		if err != nil {
+			return nil, err
+		}
+
+		customFieldName := name
+		if isStandard {
+			customFieldName = standard.customFieldName
+		}
+		field := findField(allFields, customFieldName)
+		if field == nil {
+			if isStandard && standard.value != nil {
+				columns = append(columns, Column{Name: name, Header: standard.header,
+					FieldID: standard.fieldID, value: standard.value})
+				continue
+			}
+			return nil, fmt.Errorf("unknown column %q: not a standard column nor a field ID or name", name)
+		}
+
+		header := strings.ToUpper(field.Name)
+		if isStandard {
+			header = standard.header
+		}
+		fieldID := field.ID
+		columns = append(columns, Column{Name: name, Header: header, FieldID: fieldID,
+			value: func(i *jira.Issue) string { return formatFieldValue(i.Fields.Unknowns[fieldID]) }})
+	}
+
+	return columns, nil
+}
+
+// defaultColumns returns the columns displayed when none is specified
+func defaultColumns() []Column {
+	columns := make([]Column, len(DefaultColumns))
+	for i, name := range DefaultColumns {
+		standard := standardColumns[name]
+		columns[i] = Column{Name: name, Header: standard.header, FieldID: standard.fieldID,
+			value: standard.value, sortValue: standard.sortValue}
+	}
+	return columns
+}
+
+// findField returns the field with ID or name (case insensitive) nameOrID
+func findField(fields []jira.Field, nameOrID string) *jira.Field {
+	for i := range fields {
+		if fields[i].ID == nameOrID {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].Name, nameOrID) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// sprintName matches the name in the string representation of a sprint
+// returned by Jira Server, e.g. "com.atlassian.greenhopper.service.sprint.Sprint@1[id=1,name=S1,...]"
+var sprintName = regexp.MustCompile(`name=([^,\]]*)`)
+
+// formatFieldValue returns the string representation of a custom field value
+func formatFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if m := sprintName.FindStringSubmatch(v); m != nil && strings.Contains(v, "sprint.Sprint@") {
+			return m[1]
+		}
+		return v
+	case float64:
+		return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%f", v), "0"), ".")
+	case map[string]interface{}:
+		for _, key := range []string{"name", "value", "displayName", "key"} {
+			if s, ok := v[key]; ok {
+				return formatFieldValue(s)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = fmt.Sprintf("%s=%s", k, formatFieldValue(v[k]))
+		}
+		return strings.Join(pairs, " ")
+	case []interface{}:
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = formatFieldValue(v[i])
+		}
+		return strings.Join(values, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func statusName(issue *jira.Issue) string {
+	if issue.Fields.Status == nil {
+		return "N/A"
+	}
+	return issue.Fields.Status.Name
+}
+
+func lastUpdate(issue *jira.Issue) string {
+	return fmt.Sprintf("%d days", int(time.Since(time.Time(issue.Fields.Updated)).Hours()/24))
+}
+
+func userName(user *jira.User) string {
+	if user == nil {
+		return ""
+	}
+	return user.Name
+}
+
+// formatDate returns t in format 2006-01-02 or an empty string if t is not set
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
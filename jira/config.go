@@ -0,0 +1,111 @@
+package jira
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// configPath is the name of the env variable with the config file path
+	// (~/.config/jira_utils/config.yaml by default)
+	configPath = "JIRA_UTILS_CONFIG"
+)
+
+// Config is the content of the jira_utils config file
+type Config struct {
+	// DefaultProfile is the profile used when none is selected with
+	// --profile or env variable JIRA_PROFILE
+	DefaultProfile string `yaml:"defaultProfile,omitempty"`
+	// Profiles are named sets of settings (base URL, project, board, credentials)
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+	// Queries are the saved queries, keyed by name
+	Queries map[string]SavedQuery `yaml:"queries,omitempty"`
+
+	// path is the file config was loaded from
+	path string
+}
+
+// Path returns the file config was loaded from
+func (c *Config) Path() string {
+	return c.path
+}
+
+// DefaultConfigPath returns the config file path: value of env variable
+// JIRA_UTILS_CONFIG if set, ~/.config/jira_utils/config.yaml otherwise
+func DefaultConfigPath() (string, error) {
+	if path, ok := os.LookupEnv(configPath); ok && path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "jira_utils", "config.yaml"), nil
+}
+
+// configDir returns the directory of the config file in use, where files
+// managed by jira_utils (OAuth tokens, secrets) are stored by default
+func configDir() (string, error) {
+	configFile := activeSettings.config.Path()
+	if configFile == "" {
+		var err error
+		configFile, err = DefaultConfigPath()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Dir(configFile), nil
+}
+
+// LoadConfig reads the config file path. If path is empty, DefaultConfigPath is used.
+// A missing config file is not an error: an empty Config is returned.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		var err error
+		path, err = DefaultConfigPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := &Config{path: path}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if config.DefaultProfile != "" {
+		if _, ok := config.Profiles[config.DefaultProfile]; !ok {
+			return nil, fmt.Errorf("config file %s: default profile %q is not defined", path, config.DefaultProfile)
+		}
+	}
+
+	for name := range config.Queries {
+		if config.Queries[name].JQL == "" {
+			return nil, fmt.Errorf("config file %s: saved query %q has no jql", path, name)
+		}
+	}
+
+	return config, nil
+}
+
+// ProfileNames returns the sorted names of the profiles in config
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
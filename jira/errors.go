@@ -0,0 +1,52 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by this package, wrapped with details. Use errors.Is to check for them.
+var (
+	// ErrMissingConfig is returned when a setting needed by an operation is not set
+	ErrMissingConfig = errors.New("missing configuration")
+	// ErrAuthFailed is returned when Jira rejects the credentials (see AuthError)
+	ErrAuthFailed = errors.New("authentication failed")
+	// ErrNotFound is returned when a project, board, sprint or issue does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrAmbiguousBoard is returned when more than one board matches the board name
+	ErrAmbiguousBoard = errors.New("ambiguous board")
+	// ErrNoActiveSprint is returned when no sprint of the board is active
+	ErrNoActiveSprint = errors.New("no active sprint")
+	// ErrAmbiguousSprint is returned when a single active sprint is needed and more than one is active
+	ErrAmbiguousSprint = errors.New("ambiguous sprint")
+	// ErrBudgetExceeded is returned when the client already sent as many requests as allowed (see RetryOptions)
+	ErrBudgetExceeded = errors.New("request budget exceeded")
+)
+
+// MissingSettingsError is returned when settings are not set. It matches ErrMissingConfig.
+type MissingSettingsError struct {
+	// Settings are the names of the settings not set
+	Settings []string
+	// Profile and ConfigFile are the profile and config file in use, if any
+	Profile    string
+	ConfigFile string
+}
+
+func (e *MissingSettingsError) Error() string {
+	missing := make([]string, len(e.Settings))
+	for i, name := range e.Settings {
+		missing[i] = fmt.Sprintf("%s (env variable %s)", name, settingEnvVariables[name])
+	}
+
+	where := "set the env variables or use a config file profile"
+	if e.Profile != "" {
+		where = fmt.Sprintf("set the env variables or add them to profile %q in %s", e.Profile, e.ConfigFile)
+	}
+	return fmt.Sprintf("missing settings: %s. Either %s", strings.Join(missing, ", "), where)
+}
+
+// Is makes errors.Is(err, ErrMissingConfig) true
+func (e *MissingSettingsError) Is(target error) bool {
+	return target == ErrMissingConfig
+}
@@ -0,0 +1,112 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// sessionCookie is the name of the cookie returned by rest/auth/1/session
+	sessionCookie = "JSESSIONID"
+	// defaultSession is the session cookie value used when Auth.Session is not set
+	defaultSession = "fake-session"
+)
+
+// Auth are the credentials accepted by the fake server
+type Auth struct {
+	// Username and Password are accepted with basic auth and to log in at rest/auth/1/session
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Token is accepted as bearer token (personal access token) and,
+	// with Username, as API token with basic auth (Jira Cloud)
+	Token string `json:"token"`
+	// Session is the JSESSIONID cookie value returned by rest/auth/1/session
+	// and accepted afterwards (fake-session by default)
+	Session string `json:"session"`
+	// ClientID and ClientSecret, if set, enable the OAuth 2.0 endpoints
+	// (rest/oauth2/latest/authorize and rest/oauth2/latest/token)
+	ClientID     string `json:"clientID"`
+	ClientSecret string `json:"clientSecret"`
+	// ConsumerKey and PublicKey (PEM encoded), if set, enable the OAuth 1.0a endpoints of application
+	// links (plugins/servlet/oauth/request-token, authorize and access-token)
+	ConsumerKey string `json:"consumerKey"`
+	PublicKey   string `json:"publicKey"`
+	// TokenTTL is the lifetime in seconds of OAuth access tokens (one hour by default)
+	TokenTTL int `json:"tokenTTL"`
+
+	tokens oauthTokens
+}
+
+func (a *Auth) session() string {
+	if a.Session != "" {
+		return a.Session
+	}
+	return defaultSession
+}
+
+// authenticated returns true if r carries credentials accepted by a
+func (a *Auth) authenticated(r *http.Request) bool {
+	if username, password, ok := r.BasicAuth(); ok {
+		return username == a.Username &&
+			((a.Password != "" && password == a.Password) || (a.Token != "" && password == a.Token))
+	}
+	if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); token != r.Header.Get("Authorization") {
+		return (a.Token != "" && token == a.Token) || a.validAccessToken(token)
+	}
+	if strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") {
+		return a.validOAuth1Request(r)
+	}
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		return cookie.Value == a.session()
+	}
+	return false
+}
+
+// authenticate rejects, as Jira does, requests whose credentials are not
+// accepted by client Auth. If client Auth is not set, any request is accepted.
+func (h *handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := h.client.Auth
+		if auth == nil || auth.authenticated(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("X-Seraph-LoginReason", "AUTHENTICATED_FAILED")
+		w.Header().Set("WWW-Authenticate", `Basic realm="protected-area"`)
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("you are not authenticated. Authentication required to perform this operation"))
+	})
+}
+
+// session serves POST rest/auth/1/session, logging in with username and password
+func (h *handler) session(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var login struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&login); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	auth := h.client.Auth
+	if auth != nil && (login.Username != auth.Username || auth.Password == "" || login.Password != auth.Password) {
+		w.Header().Set("X-Seraph-LoginReason", "AUTHENTICATED_FAILED")
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("login failed"))
+		return
+	}
+
+	value := defaultSession
+	if auth != nil {
+		value = auth.session()
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: value, Path: "/"})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"session": map[string]string{"name": sessionCookie, "value": value},
+	})
+}
@@ -0,0 +1,321 @@
+// Package fake contains an in-memory implementation of the JiraAPI
+// interface defined in package jira. It is meant to be used in unit
+// tests, so that commands can run without a Jira server.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+
+	jirautils "github.com/gianlucam76/jira_utils/jira"
+)
+
+// Client is an in-memory jira.JiraAPI. Exported fields can be used to
+// seed the dataset and, after running the code under test, to verify it.
+type Client struct {
+	mu sync.Mutex
+
+	Projects []jira.Project
+	Boards   []jira.Board
+	// BoardProjects contains the project key of each board, keyed by board ID
+	BoardProjects map[int]string
+	// Sprints contains the sprints of each board, keyed by board ID
+	Sprints map[int][]jirautils.Sprint
+	Issues  []jira.Issue
+	// Transitions contains the transitions available for each issue, keyed by issue ID
+	Transitions map[string][]jira.Transition
+	// Comments contains the comments added to each issue, keyed by issue ID
+	Comments map[string][]jira.Comment
+	// Fields contains the system and custom issue fields
+	Fields []jira.Field
+	// Auth, if set, are the only credentials accepted by the fake server
+	Auth *Auth
+}
+
+var _ jirautils.JiraAPI = &Client{}
+
+// NewClient returns a new, empty, fake client
+func NewClient() *Client {
+	return &Client{
+		BoardProjects: make(map[int]string),
+		Sprints:       make(map[int][]jirautils.Sprint),
+		Transitions:   make(map[string][]jira.Transition),
+		Comments:      make(map[string][]jira.Comment),
+	}
+}
+
+func (c *Client) GetProject(ctx context.Context, projectName string) (*jira.Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.Projects {
+		if c.Projects[i].Key == projectName || c.Projects[i].Name == projectName ||
+			c.Projects[i].ID == projectName {
+			project := c.Projects[i]
+			return &project, nil
+		}
+	}
+	return nil, fmt.Errorf("project %s: %w", projectName, jirautils.ErrNotFound)
+}
+
+func (c *Client) GetAllBoards(ctx context.Context, options *jira.BoardListOptions) ([]jira.Board, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	boards := make([]jira.Board, 0)
+	for i := range c.Boards {
+		if options != nil {
+			// as Jira, name matches any board whose name contains it, ignoring case
+			if options.Name != "" && !strings.Contains(strings.ToLower(c.Boards[i].Name), strings.ToLower(options.Name)) {
+				continue
+			}
+			if options.BoardType != "" && c.Boards[i].Type != options.BoardType {
+				continue
+			}
+			if options.ProjectKeyOrID != "" && !c.boardInProject(c.Boards[i].ID, options.ProjectKeyOrID) {
+				continue
+			}
+		}
+		boards = append(boards, c.Boards[i])
+	}
+	return boards, nil
+}
+
+func (c *Client) GetAllSprints(ctx context.Context, boardID string, options *jira.GetAllSprintsOptions) ([]jirautils.Sprint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, err := strconv.Atoi(boardID)
+	if err != nil {
+		return nil, err
+	}
+	sprints := make([]jirautils.Sprint, 0, len(c.Sprints[id]))
+	for i := range c.Sprints[id] {
+		// as Jira, state is a comma separated list of states
+		if options != nil && options.State != "" && !containsFold(strings.Split(options.State, ","), c.Sprints[id][i].State) {
+			continue
+		}
+		sprints = append(sprints, c.Sprints[id][i])
+	}
+	return sprints, nil
+}
+
+// containsFold returns true if values contains value, ignoring case and spaces around values
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) SearchIssues(ctx context.Context, jql string, options *jira.SearchOptions) ([]jira.Issue, int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clauses, err := parseJQL(jql)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	issues := make([]jira.Issue, 0)
+	for i := range c.Issues {
+		ok, err := matches(&c.Issues[i], clauses)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			expand := ""
+			if options != nil {
+				expand = options.Expand
+			}
+			issues = append(issues, copyIssue(&c.Issues[i], expand))
+		}
+	}
+
+	total := len(issues)
+	if options != nil {
+		if options.StartAt >= len(issues) {
+			return []jira.Issue{}, total, nil
+		}
+		issues = issues[options.StartAt:]
+		if options.MaxResults != 0 && options.MaxResults < len(issues) {
+			issues = issues[:options.MaxResults]
+		}
+	}
+
+	return issues, total, nil
+}
+
+func (c *Client) GetIssue(ctx context.Context, issueID string, options *jira.GetQueryOptions) (*jira.Issue, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	issue := c.findIssue(issueID)
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s: %w", issueID, jirautils.ErrNotFound)
+	}
+
+	expand := ""
+	if options != nil {
+		expand = options.Expand
+	}
+	result := copyIssue(issue, expand)
+	return &result, nil
+}
+
+func (c *Client) CreateIssue(ctx context.Context, issue *jira.Issue) (*jira.Issue, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if issue.Fields == nil {
+		return nil, fmt.Errorf("issue fields must be set")
+	}
+
+	created := *issue
+	fields := *issue.Fields
+	created.Fields = &fields
+	created.ID = strconv.Itoa(10000 + len(c.Issues))
+	created.Key = fmt.Sprintf("%s-%d", fields.Project.Key, len(c.Issues)+1)
+	if fields.Status == nil {
+		fields.Status = &jira.Status{Name: "Open"}
+	}
+	now := jira.Time(time.Now())
+	fields.Created = now
+	fields.Updated = now
+
+	c.Issues = append(c.Issues, created)
+	return &jira.Issue{ID: created.ID, Key: created.Key}, nil
+}
+
+func (c *Client) AddComment(ctx context.Context, issueID string, comment *jira.Comment) (*jira.Comment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	issue := c.findIssue(issueID)
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s: %w", issueID, jirautils.ErrNotFound)
+	}
+
+	added := *comment
+	added.ID = strconv.Itoa(len(c.Comments[issue.ID]) + 1)
+	c.Comments[issue.ID] = append(c.Comments[issue.ID], added)
+	return &added, nil
+}
+
+func (c *Client) MoveIssuesToSprint(ctx context.Context, sprintID int, issueIDs []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var sprint *jira.Sprint
+	for boardID := range c.Sprints {
+		for i := range c.Sprints[boardID] {
+			if c.Sprints[boardID][i].ID == sprintID {
+				sprint = &c.Sprints[boardID][i].Sprint
+			}
+		}
+	}
+	if sprint == nil {
+		return fmt.Errorf("sprint %d: %w", sprintID, jirautils.ErrNotFound)
+	}
+
+	for _, issueID := range issueIDs {
+		issue := c.findIssue(issueID)
+		if issue == nil {
+			return fmt.Errorf("issue %s: %w", issueID, jirautils.ErrNotFound)
+		}
+		s := *sprint
+		issue.Fields.Sprint = &s
+	}
+	return nil
+}
+
+func (c *Client) GetTransitions(ctx context.Context, issueID string) ([]jira.Transition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	issue := c.findIssue(issueID)
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s: %w", issueID, jirautils.ErrNotFound)
+	}
+	return c.Transitions[issue.ID], nil
+}
+
+func (c *Client) DoTransition(ctx context.Context, issueID, transitionID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	issue := c.findIssue(issueID)
+	if issue == nil {
+		return fmt.Errorf("issue %s: %w", issueID, jirautils.ErrNotFound)
+	}
+
+	for _, t := range c.Transitions[issue.ID] {
+		if t.ID == transitionID {
+			status := t.To
+			issue.Fields.Status = &status
+			issue.Fields.Updated = jira.Time(time.Now())
+			return nil
+		}
+	}
+	return fmt.Errorf("transition %s not available for issue %s", transitionID, issueID)
+}
+
+func (c *Client) GetFields(ctx context.Context) ([]jira.Field, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fields := make([]jira.Field, len(c.Fields))
+	copy(fields, c.Fields)
+	return fields, nil
+}
+
+// boardInProject returns true if board boardID belongs to project
+// with key or ID projectKeyOrID. Boards with no project belong to all
+// projects. Caller must hold c.mu.
+func (c *Client) boardInProject(boardID int, projectKeyOrID string) bool {
+	key, ok := c.BoardProjects[boardID]
+	if !ok {
+		return true
+	}
+	if key == projectKeyOrID {
+		return true
+	}
+	for i := range c.Projects {
+		if c.Projects[i].Key == key && c.Projects[i].ID == projectKeyOrID {
+			return true
+		}
+	}
+	return false
+}
+
+// findIssue returns the issue with passed ID or key. Caller must hold c.mu.
+func (c *Client) findIssue(issueID string) *jira.Issue {
+	for i := range c.Issues {
+		if c.Issues[i].ID == issueID || c.Issues[i].Key == issueID {
+			return &c.Issues[i]
+		}
+	}
+	return nil
+}
+
+// copyIssue returns a copy of issue. Changelog is only included
+// if expand contains "changelog", as the real Jira API does.
+func copyIssue(issue *jira.Issue, expand string) jira.Issue {
+	result := *issue
+	if issue.Fields != nil {
+		fields := *issue.Fields
+		result.Fields = &fields
+	}
+	if !strings.Contains(expand, "changelog") {
+		result.Changelog = nil
+	}
+	return result
+}
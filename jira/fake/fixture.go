@@ -0,0 +1,124 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"gopkg.in/yaml.v3"
+
+	jirautils "github.com/gianlucam76/jira_utils/jira"
+)
+
+// Fixture is the dataset used to seed a fake Client.
+// Objects use the same field names as the Jira REST API, so that
+// fixtures can be written by copying (and trimming) real responses.
+// Issue times (fields.created, fields.updated, changelog created) use
+// the Jira format "2006-01-02T15:04:05.000-0700", sprint dates RFC3339.
+type Fixture struct {
+	Projects []jira.Project `json:"projects"`
+	Boards   []FixtureBoard `json:"boards"`
+	// Sprints contains the sprints of each board, keyed by board ID
+	Sprints map[int][]jirautils.Sprint `json:"sprints"`
+	Issues  []jira.Issue               `json:"issues"`
+	// Transitions contains the transitions available for each issue, keyed by issue ID
+	Transitions map[string][]jira.Transition `json:"transitions"`
+	// Fields contains the system and custom issue fields
+	Fields []jira.Field `json:"fields"`
+	// Auth, if set, are the only credentials accepted by the fake server
+	Auth *Auth `json:"auth"`
+}
+
+// FixtureBoard is a board and the key of the project it belongs to
+type FixtureBoard struct {
+	jira.Board
+	Project string `json:"project"`
+}
+
+// LoadFixture reads a YAML or JSON fixture from file path and returns
+// a fake Client seeded with it
+func LoadFixture(path string) (*Client, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture, err := ParseFixture(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	return NewClientFromFixture(fixture), nil
+}
+
+// ParseFixture parses a YAML or JSON (being YAML a superset of JSON) fixture
+func ParseFixture(content []byte) (*Fixture, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	// go-jira types only carry json tags, so convert to JSON first
+	data, err := json.Marshal(toJSONCompatible(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{}
+	if err := json.Unmarshal(data, fixture); err != nil {
+		return nil, err
+	}
+	return fixture, nil
+}
+
+// NewClientFromFixture returns a fake Client seeded with fixture
+func NewClientFromFixture(fixture *Fixture) *Client {
+	c := NewClient()
+	c.Projects = fixture.Projects
+	for i := range fixture.Boards {
+		c.Boards = append(c.Boards, fixture.Boards[i].Board)
+		if fixture.Boards[i].Project != "" {
+			c.BoardProjects[fixture.Boards[i].ID] = fixture.Boards[i].Project
+		}
+	}
+	for boardID := range fixture.Sprints {
+		c.Sprints[boardID] = fixture.Sprints[boardID]
+	}
+	c.Issues = fixture.Issues
+	for issueID := range fixture.Transitions {
+		c.Transitions[issueID] = fixture.Transitions[issueID]
+	}
+	c.Fields = fixture.Fields
+	c.Auth = fixture.Auth
+	return c
+}
+
+// toJSONCompatible converts the generic value returned by yaml.Unmarshal
+// into one encoding/json can marshal (maps with string keys only)
+func toJSONCompatible(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k := range value {
+			value[k] = toJSONCompatible(value[k])
+		}
+		return value
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k := range value {
+			m[fmt.Sprint(k)] = toJSONCompatible(value[k])
+		}
+		return m
+	case []interface{}:
+		for i := range value {
+			value[i] = toJSONCompatible(value[i])
+		}
+		return value
+	case time.Time:
+		// unquoted YAML timestamps are used for sprint dates
+		return value.Format(time.RFC3339)
+	default:
+		return value
+	}
+}
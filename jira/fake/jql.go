@@ -0,0 +1,217 @@
+package fake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// clause is a single JQL condition like "status NOT IN (Resolved,Closed)"
+// or "updated >= 2022-04-18"
+type clause struct {
+	field  string
+	negate bool
+	values []string
+	// op is the comparison operator (>=, <=, > or <) of date clauses, empty otherwise
+	op string
+	// date is the value of date clauses
+	date time.Time
+}
+
+var (
+	// andSeparator splits a JQL expression in its clauses
+	andSeparator = regexp.MustCompile(`(?i)\s+and\s+`)
+	// orderBy matches the (ignored) ORDER BY part of a JQL expression
+	orderBy = regexp.MustCompile(`(?i)(^|\s+)order\s+by\s+.*$`)
+	// inClause matches "field IN (a,b)" and "field NOT IN (a,b)"
+	inClause = regexp.MustCompile(`(?i)^\s*([\w"' ]+?)\s+(not\s+in|in)\s*\((.*)\)\s*$`)
+	// compareClause matches "field >= a", "field <= a", "field > a" and "field < a"
+	compareClause = regexp.MustCompile(`^\s*([\w"' ]+?)\s*(>=|<=|>|<)\s*(.+?)\s*$`)
+	// eqClause matches "field = a" and "field != a"
+	eqClause = regexp.MustCompile(`^\s*([\w"' ]+?)\s*(!=|=)\s*(.+?)\s*$`)
+)
+
+// dateLayouts are the JQL date formats supported by the fake, in UTC
+var dateLayouts = []string{"2006-01-02 15:04", "2006/01/02 15:04", "2006-01-02", "2006/01/02"}
+
+// parseJQL parses the subset of JQL supported by the fake: clauses
+// using =, !=, IN and NOT IN, and created/updated compared to a date
+// with >=, <=, > and <, joined by AND. ORDER BY is ignored.
+func parseJQL(jql string) ([]clause, error) {
+	jql = orderBy.ReplaceAllString(strings.TrimSpace(jql), "")
+	if jql == "" {
+		return nil, nil
+	}
+
+	clauses := make([]clause, 0)
+	for _, c := range andSeparator.Split(jql, -1) {
+		if m := inClause.FindStringSubmatch(c); m != nil {
+			values := make([]string, 0)
+			for _, v := range strings.Split(m[3], ",") {
+				values = append(values, unquote(v))
+			}
+			clauses = append(clauses, clause{
+				field:  strings.ToLower(unquote(m[1])),
+				negate: strings.ToLower(strings.Join(strings.Fields(m[2]), " ")) == "not in",
+				values: values,
+			})
+			continue
+		}
+		if m := compareClause.FindStringSubmatch(c); m != nil {
+			cl, err := parseCompareClause(strings.ToLower(unquote(m[1])), m[2], unquote(m[3]))
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, cl)
+			continue
+		}
+		if m := eqClause.FindStringSubmatch(c); m != nil {
+			clauses = append(clauses, clause{
+				field:  strings.ToLower(unquote(m[1])),
+				negate: m[2] == "!=",
+				values: []string{unquote(m[3])},
+			})
+			continue
+		}
+		return nil, fmt.Errorf("unsupported JQL clause %q", c)
+	}
+
+	return clauses, nil
+}
+
+// parseCompareClause returns the clause comparing date field to value with op
+func parseCompareClause(field, op, value string) (clause, error) {
+	if field != "created" && field != "updated" {
+		return clause{}, fmt.Errorf("unsupported JQL comparison on field %q", field)
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return clause{field: field, op: op, date: date}, nil
+		}
+	}
+	return clause{}, fmt.Errorf("unsupported JQL date %q", value)
+}
+
+// matches returns true if issue satisfies all clauses
+func matches(issue *jira.Issue, clauses []clause) (bool, error) {
+	for i := range clauses {
+		if clauses[i].op != "" {
+			if !matchesDate(issue, &clauses[i]) {
+				return false, nil
+			}
+			continue
+		}
+		value, err := fieldValue(issue, clauses[i].field)
+		if err != nil {
+			return false, err
+		}
+		found := false
+		for _, v := range clauses[i].values {
+			if strings.EqualFold(v, value) || matchesSprintID(issue, clauses[i].field, v) {
+				found = true
+				break
+			}
+		}
+		if found == clauses[i].negate {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchesDate returns true if the created or updated time of issue satisfies date clause c
+func matchesDate(issue *jira.Issue, c *clause) bool {
+	if issue.Fields == nil {
+		return false
+	}
+	t := time.Time(issue.Fields.Updated)
+	if c.field == "created" {
+		t = time.Time(issue.Fields.Created)
+	}
+
+	switch c.op {
+	case ">=":
+		return !t.Before(c.date)
+	case "<=":
+		return !t.After(c.date)
+	case ">":
+		return t.After(c.date)
+	default:
+		return t.Before(c.date)
+	}
+}
+
+// matchesSprintID returns true if field is sprint and value is the ID of the issue sprint,
+// since sprints can be selected by name or ID
+func matchesSprintID(issue *jira.Issue, field, value string) bool {
+	return field == "sprint" && issue.Fields != nil && issue.Fields.Sprint != nil &&
+		fmt.Sprintf("%d", issue.Fields.Sprint.ID) == value
+}
+
+// fieldValue returns the value of issue field as used in JQL comparisons
+func fieldValue(issue *jira.Issue, field string) (string, error) {
+	if field == "key" || field == "issuekey" {
+		return issue.Key, nil
+	}
+	if field == "id" {
+		return issue.ID, nil
+	}
+
+	fields := issue.Fields
+	if fields == nil {
+		return "", nil
+	}
+
+	switch field {
+	case "status":
+		if fields.Status != nil {
+			return fields.Status.Name, nil
+		}
+	case "sprint":
+		if fields.Sprint != nil {
+			return fields.Sprint.Name, nil
+		}
+	case "assignee":
+		return userName(fields.Assignee), nil
+	case "reporter":
+		return userName(fields.Reporter), nil
+	case "project":
+		if fields.Project.Key != "" {
+			return fields.Project.Key, nil
+		}
+		return fields.Project.Name, nil
+	case "type", "issuetype":
+		return fields.Type.Name, nil
+	case "priority":
+		if fields.Priority != nil {
+			return fields.Priority.Name, nil
+		}
+	case "summary":
+		return fields.Summary, nil
+	default:
+		return "", fmt.Errorf("unsupported JQL field %q", field)
+	}
+
+	return "", nil
+}
+
+func userName(user *jira.User) string {
+	if user == nil {
+		return ""
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.AccountID
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
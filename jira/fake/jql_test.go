@@ -0,0 +1,101 @@
+package fake
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+func TestParseJQL(t *testing.T) {
+	tests := []struct {
+		jql     string
+		want    []clause
+		wantErr bool
+	}{
+		{jql: "", want: nil},
+		{jql: "  ORDER BY created", want: nil},
+		{jql: `project = "CLOUDSTACK"`, want: []clause{{field: "project", values: []string{"CLOUDSTACK"}}}},
+		{jql: "Status != Closed ORDER BY updated DESC",
+			want: []clause{{field: "status", negate: true, values: []string{"Closed"}}}},
+		{jql: "status NOT IN (Resolved, 'Closed') and assignee in (mgianluc)", want: []clause{
+			{field: "status", negate: true, values: []string{"Resolved", "Closed"}},
+			{field: "assignee", values: []string{"mgianluc"}},
+		}},
+		{jql: `"Sprint" = 12 AND updated >= "2022-04-17"`, want: []clause{
+			{field: "sprint", values: []string{"12"}},
+			{field: "updated", op: ">=", date: time.Date(2022, 4, 17, 0, 0, 0, 0, time.UTC)},
+		}},
+		{jql: "created < 2022/04/01 10:30", want: []clause{
+			{field: "created", op: "<", date: time.Date(2022, 4, 1, 10, 30, 0, 0, time.UTC)}}},
+		{jql: "summary ~ registry", wantErr: true},
+		{jql: "priority > Major", wantErr: true},
+		{jql: "updated >= -1d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseJQL(tt.jql)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseJQL(%q): got error %v, want error %t", tt.jql, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseJQL(%q) = %+v, want %+v", tt.jql, got, tt.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	updated := time.Date(2022, 4, 21, 15, 0, 0, 0, time.UTC)
+	issue := &jira.Issue{Key: "CLOUDSTACK-2340", ID: "20007", Fields: &jira.IssueFields{
+		Project:  jira.Project{Key: "CLOUDSTACK", Name: "CloudStack"},
+		Status:   &jira.Status{Name: "In Progress"},
+		Assignee: &jira.User{Name: "vikasd"},
+		Sprint:   &jira.Sprint{ID: 12, Name: "Sprint-42"},
+		Type:     jira.IssueType{Name: "Task"},
+		Created:  jira.Time(updated.AddDate(0, 0, -9)),
+		Updated:  jira.Time(updated),
+	}}
+
+	tests := []struct {
+		jql     string
+		want    bool
+		wantErr bool
+	}{
+		{jql: "", want: true},
+		{jql: "key = CLOUDSTACK-2340", want: true},
+		{jql: "project = KUBE", want: false},
+		{jql: "project = cloudstack", want: true},
+		{jql: "status = 'in progress'", want: true},
+		{jql: "status != 'In Progress'", want: false},
+		{jql: "status in (Backlog, \"In Progress\")", want: true},
+		{jql: "status not in (Backlog, \"In Progress\")", want: false},
+		{jql: "sprint = 12", want: true},
+		{jql: "sprint = Sprint-42 and assignee = vikasd and type = Task", want: true},
+		{jql: "sprint = 13", want: false},
+		{jql: "reporter = vikasd", want: false},
+		{jql: "updated >= 2022-04-21", want: true},
+		{jql: "updated >= \"2022-04-21 15:00\"", want: true},
+		{jql: "updated > \"2022-04-21 15:00\"", want: false},
+		{jql: "updated < 2022-04-22", want: true},
+		{jql: "updated <= 2022-04-21", want: false},
+		{jql: "created >= 2022-04-13", want: false},
+		{jql: "labels = flaky", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		clauses, err := parseJQL(tt.jql)
+		if err != nil {
+			t.Fatalf("parseJQL(%q): %v", tt.jql, err)
+		}
+		got, err := matches(issue, clauses)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("matches(%q): got error %v, want error %t", tt.jql, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("matches(%q) = %t, want %t", tt.jql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package fake
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// defaultTokenTTL is the OAuth access token lifetime used when Auth.TokenTTL is not set
+const defaultTokenTTL = time.Hour
+
+// oauthGrant is an authorization code issued by the authorize endpoint
+type oauthGrant struct {
+	redirectURI   string
+	codeChallenge string
+}
+
+// oauthTokens holds authorization codes and tokens issued by the fake server
+type oauthTokens struct {
+	mu      sync.Mutex
+	codes   map[string]oauthGrant
+	access  map[string]time.Time // access token => expiry
+	refresh map[string]bool
+	// requests are the OAuth 1.0a request tokens
+	requests map[string]*oauth1Request
+}
+
+func (t *oauthTokens) init() {
+	if t.codes == nil {
+		t.codes = map[string]oauthGrant{}
+		t.access = map[string]time.Time{}
+		t.refresh = map[string]bool{}
+		t.requests = map[string]*oauth1Request{}
+	}
+}
+
+// validAccessToken returns true if token was issued by the token endpoint and is not expired
+func (a *Auth) validAccessToken(token string) bool {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+	expiry, ok := a.tokens.access[token]
+	return ok && time.Now().Before(expiry)
+}
+
+func (a *Auth) tokenTTL() time.Duration {
+	if a.TokenTTL > 0 {
+		return time.Duration(a.TokenTTL) * time.Second
+	}
+	return defaultTokenTTL
+}
+
+// oauthAuthorize serves GET rest/oauth2/latest/authorize. The fake server does
+// not ask for consent: it redirects to redirect_uri with a new authorization code.
+func (h *handler) oauthAuthorize(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	auth := h.client.Auth
+	if auth == nil || auth.ClientID == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("OAuth is not configured"))
+		return
+	}
+
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Host == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid redirect_uri"))
+		return
+	}
+
+	callback := redirectURI.Query()
+	callback.Set("state", query.Get("state"))
+	if query.Get("client_id") != auth.ClientID || query.Get("response_type") != "code" {
+		callback.Set("error", "unauthorized_client")
+	} else {
+		code := newToken()
+		auth.tokens.mu.Lock()
+		auth.tokens.init()
+		auth.tokens.codes[code] = oauthGrant{redirectURI: redirectURI.String(), codeChallenge: query.Get("code_challenge")}
+		auth.tokens.mu.Unlock()
+		callback.Set("code", code)
+	}
+	redirectURI.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// oauthToken serves POST rest/oauth2/latest/token, for authorization_code and refresh_token grants
+func (h *handler) oauthToken(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	auth := h.client.Auth
+	if auth == nil || auth.ClientID == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("OAuth is not configured"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, "invalid_request", err.Error())
+		return
+	}
+	if r.PostForm.Get("client_id") != auth.ClientID || r.PostForm.Get("client_secret") != auth.ClientSecret {
+		writeOAuthError(w, "invalid_client", "invalid client credentials")
+		return
+	}
+
+	auth.tokens.mu.Lock()
+	defer auth.tokens.mu.Unlock()
+	auth.tokens.init()
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		grant, ok := auth.tokens.codes[r.PostForm.Get("code")]
+		if !ok || grant.redirectURI != r.PostForm.Get("redirect_uri") {
+			writeOAuthError(w, "invalid_grant", "invalid authorization code")
+			return
+		}
+		delete(auth.tokens.codes, r.PostForm.Get("code"))
+		if grant.codeChallenge != "" {
+			sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			if base64.RawURLEncoding.EncodeToString(sum[:]) != grant.codeChallenge {
+				writeOAuthError(w, "invalid_grant", "invalid code_verifier")
+				return
+			}
+		}
+	case "refresh_token":
+		if !auth.tokens.refresh[r.PostForm.Get("refresh_token")] {
+			writeOAuthError(w, "invalid_grant", "invalid refresh token")
+			return
+		}
+		delete(auth.tokens.refresh, r.PostForm.Get("refresh_token"))
+	default:
+		writeOAuthError(w, "unsupported_grant_type", r.PostForm.Get("grant_type"))
+		return
+	}
+
+	accessToken, refreshToken := newToken(), newToken()
+	auth.tokens.access[accessToken] = time.Now().Add(auth.tokenTTL())
+	auth.tokens.refresh[refreshToken] = true
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "bearer",
+		"expires_in":    int64(auth.tokenTTL() / time.Second),
+	})
+}
+
+// writeOAuthError writes an error response as defined by RFC 6749
+func writeOAuthError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
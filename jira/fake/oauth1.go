@@ -0,0 +1,241 @@
+package fake
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// oauth1Request is a request token issued by the request-token endpoint
+type oauth1Request struct {
+	callback string
+	// verifier is set once the request token is authorized
+	verifier string
+}
+
+// oauth1Params returns the protocol parameters in the OAuth Authorization header of r
+func oauth1Params(r *http.Request) (map[string]string, bool) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "OAuth ") {
+		return nil, false
+	}
+	params := map[string]string{}
+	for _, pair := range strings.Split(strings.TrimPrefix(header, "OAuth "), ",") {
+		nameValue := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(nameValue) != 2 {
+			return nil, false
+		}
+		name, err := url.PathUnescape(nameValue[0])
+		if err != nil {
+			return nil, false
+		}
+		value, err := url.PathUnescape(strings.Trim(nameValue[1], `"`))
+		if err != nil {
+			return nil, false
+		}
+		params[name] = value
+	}
+	return params, true
+}
+
+// oauth1Problem returns the reason r is not signed by the application link consumer, as
+// reported by Jira in oauth_problem, or an empty string if the signature is valid
+func (a *Auth) oauth1Problem(r *http.Request, params map[string]string) string {
+	if params["oauth_consumer_key"] != a.ConsumerKey {
+		return "consumer_key_unknown"
+	}
+	if params["oauth_signature_method"] != "RSA-SHA1" {
+		return "signature_method_rejected"
+	}
+	timestamp, err := strconv.ParseInt(params["oauth_timestamp"], 10, 64)
+	if skew := time.Since(time.Unix(timestamp, 0)); err != nil || skew > 5*time.Minute || skew < -5*time.Minute {
+		return "timestamp_refused"
+	}
+
+	block, _ := pem.Decode([]byte(a.PublicKey))
+	if block == nil {
+		return "consumer_key_unknown"
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return "consumer_key_unknown"
+	}
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return "consumer_key_unknown"
+	}
+	signature, err := base64.StdEncoding.DecodeString(params["oauth_signature"])
+	if err != nil {
+		return "signature_invalid"
+	}
+	sum := sha1.Sum([]byte(signatureBaseString(r, params)))
+	if rsa.VerifyPKCS1v15(publicKey, crypto.SHA1, sum[:], signature) != nil {
+		return "signature_invalid"
+	}
+	return ""
+}
+
+// signatureBaseString returns the OAuth 1.0a signature base string of r (RFC 5849 section 3.4.1)
+func signatureBaseString(r *http.Request, params map[string]string) string {
+	escape := func(s string) string { return strings.ReplaceAll(url.QueryEscape(s), "+", "%20") }
+	all := make([][2]string, 0, len(params))
+	for name, values := range r.URL.Query() {
+		for _, value := range values {
+			all = append(all, [2]string{escape(name), escape(value)})
+		}
+	}
+	for name, value := range params {
+		if name != "oauth_signature" {
+			all = append(all, [2]string{escape(name), escape(value)})
+		}
+	}
+	// sorted by name, then value
+	sort.Slice(all, func(i, j int) bool {
+		return all[i][0] < all[j][0] || (all[i][0] == all[j][0] && all[i][1] < all[j][1])
+	})
+	pairs := make([]string, len(all))
+	for i := range all {
+		pairs[i] = all[i][0] + "=" + all[i][1]
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	baseURI := scheme + "://" + strings.ToLower(r.Host) + r.URL.EscapedPath()
+	return r.Method + "&" + escape(baseURI) + "&" + escape(strings.Join(pairs, "&"))
+}
+
+// validOAuth1Request returns true if r is signed by the application link consumer
+// with an access token issued by the access-token endpoint and not expired
+func (a *Auth) validOAuth1Request(r *http.Request) bool {
+	params, ok := oauth1Params(r)
+	if !ok || a.ConsumerKey == "" || a.oauth1Problem(r, params) != "" {
+		return false
+	}
+	return a.validAccessToken(params["oauth_token"])
+}
+
+// oauth1Authenticate returns the protocol parameters of r, writing an error
+// response if OAuth 1.0a is not configured or r is not correctly signed
+func (h *handler) oauth1Authenticate(w http.ResponseWriter, r *http.Request) (*Auth, map[string]string, bool) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return nil, nil, false
+	}
+	auth := h.client.Auth
+	if auth == nil || auth.ConsumerKey == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("OAuth 1.0a is not configured"))
+		return nil, nil, false
+	}
+	params, ok := oauth1Params(r)
+	if !ok {
+		writeOAuth1Problem(w, "parameter_absent")
+		return nil, nil, false
+	}
+	if problem := auth.oauth1Problem(r, params); problem != "" {
+		writeOAuth1Problem(w, problem)
+		return nil, nil, false
+	}
+	return auth, params, true
+}
+
+// oauth1RequestToken serves POST plugins/servlet/oauth/request-token
+func (h *handler) oauth1RequestToken(w http.ResponseWriter, r *http.Request) {
+	auth, params, ok := h.oauth1Authenticate(w, r)
+	if !ok {
+		return
+	}
+	if _, err := url.Parse(params["oauth_callback"]); err != nil || params["oauth_callback"] == "" {
+		writeOAuth1Problem(w, "parameter_rejected")
+		return
+	}
+
+	token := newToken()
+	auth.tokens.mu.Lock()
+	auth.tokens.init()
+	auth.tokens.requests[token] = &oauth1Request{callback: params["oauth_callback"]}
+	auth.tokens.mu.Unlock()
+	writeForm(w, url.Values{"oauth_token": {token}, "oauth_token_secret": {newToken()},
+		"oauth_callback_confirmed": {"true"}})
+}
+
+// oauth1Authorize serves GET plugins/servlet/oauth/authorize. The fake server does not
+// ask for consent: it redirects to the request token callback with a new verifier.
+func (h *handler) oauth1Authorize(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	auth := h.client.Auth
+	if auth == nil || auth.ConsumerKey == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("OAuth 1.0a is not configured"))
+		return
+	}
+
+	token := r.URL.Query().Get("oauth_token")
+	auth.tokens.mu.Lock()
+	auth.tokens.init()
+	request, ok := auth.tokens.requests[token]
+	if ok {
+		request.verifier = newToken()
+	}
+	auth.tokens.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request token"))
+		return
+	}
+
+	callback, _ := url.Parse(request.callback)
+	query := callback.Query()
+	query.Set("oauth_token", token)
+	query.Set("oauth_verifier", request.verifier)
+	callback.RawQuery = query.Encode()
+	http.Redirect(w, r, callback.String(), http.StatusFound)
+}
+
+// oauth1AccessToken serves POST plugins/servlet/oauth/access-token, exchanging
+// an authorized request token for an access token
+func (h *handler) oauth1AccessToken(w http.ResponseWriter, r *http.Request) {
+	auth, params, ok := h.oauth1Authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	auth.tokens.mu.Lock()
+	defer auth.tokens.mu.Unlock()
+	auth.tokens.init()
+	request, ok := auth.tokens.requests[params["oauth_token"]]
+	if !ok || request.verifier == "" || request.verifier != params["oauth_verifier"] {
+		writeOAuth1Problem(w, "token_rejected")
+		return
+	}
+	delete(auth.tokens.requests, params["oauth_token"])
+
+	accessToken := newToken()
+	auth.tokens.access[accessToken] = time.Now().Add(auth.tokenTTL())
+	writeForm(w, url.Values{"oauth_token": {accessToken}, "oauth_token_secret": {newToken()},
+		"oauth_expires_in": {strconv.FormatInt(int64(auth.tokenTTL()/time.Second), 10)}})
+}
+
+// writeOAuth1Problem writes an OAuth 1.0a error response, as Jira does
+func writeOAuth1Problem(w http.ResponseWriter, problem string) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(`OAuth realm="", oauth_problem="%s"`, problem))
+	w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+	w.WriteHeader(http.StatusUnauthorized)
+	fmt.Fprint(w, url.Values{"oauth_problem": {problem}}.Encode())
+}
+
+func writeForm(w http.ResponseWriter, values url.Values) {
+	w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, values.Encode())
+}
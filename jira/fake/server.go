@@ -0,0 +1,378 @@
+package fake
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+
+	jirautils "github.com/gianlucam76/jira_utils/jira"
+)
+
+const (
+	// defaultMaxResults is the page size used by Jira when maxResults is not set
+	defaultMaxResults = 50
+)
+
+// NewServer starts and returns an httptest.Server serving the Jira REST
+// endpoints used by jira_utils, backed by client. Point JIRA_BASE_URL to
+// the returned server URL to run any command against it.
+// Caller must Close the server.
+func NewServer(client *Client) *httptest.Server {
+	return httptest.NewServer(NewHandler(client))
+}
+
+// NewHandler returns an http.Handler serving the Jira REST endpoints
+// used by jira_utils, backed by client. If client Auth is set, only
+// requests with those credentials are accepted, any otherwise.
+func NewHandler(client *Client) http.Handler {
+	h := &handler{client: client}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/project/", h.project)
+	mux.HandleFunc("/rest/api/2/search", h.search)
+	mux.HandleFunc("/rest/api/2/field", h.fields)
+	mux.HandleFunc("/rest/api/2/issue", h.createIssue)
+	mux.HandleFunc("/rest/api/2/issue/", h.issue)
+	mux.HandleFunc("/rest/agile/1.0/issue/", h.issue)
+	mux.HandleFunc("/rest/agile/1.0/board", h.boards)
+	mux.HandleFunc("/rest/agile/1.0/board/", h.boardSprints)
+	mux.HandleFunc("/rest/agile/1.0/sprint/", h.sprintIssues)
+
+	root := http.NewServeMux()
+	root.HandleFunc("/rest/auth/1/session", h.session)
+	root.HandleFunc("/rest/oauth2/latest/authorize", h.oauthAuthorize)
+	root.HandleFunc("/rest/oauth2/latest/token", h.oauthToken)
+	root.HandleFunc("/plugins/servlet/oauth/request-token", h.oauth1RequestToken)
+	root.HandleFunc("/plugins/servlet/oauth/authorize", h.oauth1Authorize)
+	root.HandleFunc("/plugins/servlet/oauth/access-token", h.oauth1AccessToken)
+	root.Handle("/", h.authenticate(mux))
+	return root
+}
+
+type handler struct {
+	client *Client
+}
+
+// project serves GET rest/api/2/project/{projectIdOrKey}
+func (h *handler) project(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/rest/api/2/project/")
+	project, err := h.client.GetProject(r.Context(), name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeCacheableJSON(w, r, project)
+}
+
+// search serves GET rest/api/2/search
+func (h *handler) search(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	startAt, maxResults, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	issues, total, err := h.client.SearchIssues(r.Context(), query.Get("jql"),
+		&jira.SearchOptions{StartAt: startAt, MaxResults: maxResults, Expand: query.Get("expand")})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      total,
+		"issues":     issues,
+	})
+}
+
+// fields serves GET rest/api/2/field
+func (h *handler) fields(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	fields, err := h.client.GetFields(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, fields)
+}
+
+// createIssue serves POST rest/api/2/issue
+func (h *handler) createIssue(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	issue := &jira.Issue{}
+	if err := json.NewDecoder(r.Body).Decode(issue); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := h.client.CreateIssue(r.Context(), issue)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// issue serves all rest/api/2/issue/{issueIdOrKey}[/comment|/transitions]
+// and rest/agile/1.0/issue/{issueIdOrKey} endpoints
+func (h *handler) issue(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+	path = strings.TrimPrefix(path, "/rest/agile/1.0/issue/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	issueID := parts[0]
+
+	switch {
+	case len(parts) == 1:
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		issue, err := h.client.GetIssue(r.Context(), issueID,
+			&jira.GetQueryOptions{Expand: r.URL.Query().Get("expand")})
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, issue)
+	case len(parts) == 2 && parts[1] == "comment":
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		comment := &jira.Comment{}
+		if err := json.NewDecoder(r.Body).Decode(comment); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		added, err := h.client.AddComment(r.Context(), issueID, comment)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, added)
+	case len(parts) == 2 && parts[1] == "transitions":
+		h.transitions(w, r, issueID)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s", r.URL.Path))
+	}
+}
+
+// transitions serves GET and POST rest/api/2/issue/{issueIdOrKey}/transitions
+func (h *handler) transitions(w http.ResponseWriter, r *http.Request, issueID string) {
+	switch r.Method {
+	case http.MethodGet:
+		transitions, err := h.client.GetTransitions(r.Context(), issueID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if transitions == nil {
+			transitions = []jira.Transition{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"transitions": transitions})
+	case http.MethodPost:
+		payload := &jira.CreateTransitionPayload{}
+		if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.client.DoTransition(r.Context(), issueID, payload.Transition.ID); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// boards serves GET rest/agile/1.0/board
+func (h *handler) boards(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	startAt, maxResults, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	boards, err := h.client.GetAllBoards(r.Context(), &jira.BoardListOptions{
+		BoardType:      query.Get("type"),
+		Name:           query.Get("name"),
+		ProjectKeyOrID: query.Get("projectKeyOrId"),
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	values := make([]jira.Board, 0)
+	for i := startAt; i < len(boards) && i < startAt+maxResults; i++ {
+		values = append(values, boards[i])
+	}
+	writeCacheableJSON(w, r, &jira.BoardsList{
+		MaxResults: maxResults,
+		StartAt:    startAt,
+		Total:      len(boards),
+		IsLast:     startAt+maxResults >= len(boards),
+		Values:     values,
+	})
+}
+
+// boardSprints serves GET rest/agile/1.0/board/{boardId}/sprint
+func (h *handler) boardSprints(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/rest/agile/1.0/board/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "sprint" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s", r.URL.Path))
+		return
+	}
+
+	startAt, maxResults, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sprints, err := h.client.GetAllSprints(r.Context(), parts[0],
+		&jira.GetAllSprintsOptions{State: r.URL.Query().Get("state")})
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	// jira.SprintsList values have no goal
+	values := make([]jirautils.Sprint, 0)
+	for i := startAt; i < len(sprints) && i < startAt+maxResults; i++ {
+		values = append(values, sprints[i])
+	}
+	writeCacheableJSON(w, r, &struct {
+		MaxResults int                `json:"maxResults"`
+		StartAt    int                `json:"startAt"`
+		Total      int                `json:"total"`
+		IsLast     bool               `json:"isLast"`
+		Values     []jirautils.Sprint `json:"values"`
+	}{
+		MaxResults: maxResults,
+		StartAt:    startAt,
+		Total:      len(sprints),
+		IsLast:     startAt+maxResults >= len(sprints),
+		Values:     values,
+	})
+}
+
+// sprintIssues serves POST rest/agile/1.0/sprint/{sprintId}/issue
+func (h *handler) sprintIssues(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/rest/agile/1.0/sprint/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "issue" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s", r.URL.Path))
+		return
+	}
+
+	sprintID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	payload := &jira.IssuesWrapper{}
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.client.MoveIssuesToSprint(r.Context(), sprintID, payload.Issues); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pageParams returns startAt and maxResults query parameters, rejecting negative values
+func pageParams(r *http.Request) (startAt, maxResults int, err error) {
+	query := r.URL.Query()
+	maxResults = defaultMaxResults
+	if v := query.Get("startAt"); v != "" {
+		if startAt, err = strconv.Atoi(v); err != nil || startAt < 0 {
+			return 0, 0, fmt.Errorf("invalid startAt %q", v)
+		}
+	}
+	if v := query.Get("maxResults"); v != "" {
+		if maxResults, err = strconv.Atoi(v); err != nil || maxResults < 0 {
+			return 0, 0, fmt.Errorf("invalid maxResults %q", v)
+		}
+	}
+	return startAt, maxResults, nil
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeCacheableJSON writes v with an ETag, as Jira does for some resources,
+// or 304 Not Modified if the request If-None-Match is the same ETag
+func writeCacheableJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// writeError writes err in the format used by Jira for error responses
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]interface{}{
+		"errorMessages": []string{err.Error()},
+		"errors":        map[string]string{},
+	})
+}
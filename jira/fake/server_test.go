@@ -0,0 +1,53 @@
+package fake
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPageParams(t *testing.T) {
+	tests := []struct {
+		query          string
+		wantStartAt    int
+		wantMaxResults int
+		wantErr        bool
+	}{
+		{query: "", wantStartAt: 0, wantMaxResults: defaultMaxResults},
+		{query: "startAt=100&maxResults=25", wantStartAt: 100, wantMaxResults: 25},
+		{query: "maxResults=0", wantStartAt: 0, wantMaxResults: 0},
+		{query: "startAt=-1", wantErr: true},
+		{query: "maxResults=-50", wantErr: true},
+		{query: "startAt=first", wantErr: true},
+		{query: "maxResults=all", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/rest/api/2/search?"+tt.query, nil)
+		startAt, maxResults, err := pageParams(r)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("pageParams(%q): got error %v, want error %t", tt.query, err, tt.wantErr)
+			continue
+		}
+		if startAt != tt.wantStartAt || maxResults != tt.wantMaxResults {
+			t.Errorf("pageParams(%q) = %d, %d; want %d, %d", tt.query, startAt, maxResults,
+				tt.wantStartAt, tt.wantMaxResults)
+		}
+	}
+}
+
+func TestSearchRejectsNegativePage(t *testing.T) {
+	server := NewServer(NewClient())
+	defer server.Close()
+
+	for _, query := range []string{"startAt=-1", "maxResults=-1"} {
+		resp, err := server.Client().Get(server.URL + "/rest/api/2/search?jql=&" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("search with %s: got status %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
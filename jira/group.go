@@ -0,0 +1,222 @@
+package jira
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+const (
+	// noGroup is the name of the group of issues with no value for the group-by field
+	noGroup = "(none)"
+)
+
+// SortKey is a column issues are sorted by
+type SortKey struct {
+	// Column is the column name (see ResolveColumns)
+	Column string
+	// Descending sorts from the largest to the smallest value
+	Descending bool
+}
+
+// ParseSortKeys parses a comma separated list of columns, each optionally
+// followed by ":asc" (default) or ":desc", e.g. "priority,updated:desc"
+func ParseSortKeys(s string) ([]SortKey, error) {
+	keys := make([]SortKey, 0)
+	for _, k := range strings.Split(s, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		key := SortKey{Column: k}
+		if i := strings.LastIndex(k, ":"); i != -1 {
+			key.Column = k[:i]
+			switch strings.ToLower(k[i+1:]) {
+			case "asc":
+			case "desc":
+				key.Descending = true
+			default:
+				return nil, fmt.Errorf("invalid sort order %q for column %s (supported: asc, desc)", k[i+1:], key.Column)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// groupByColumns maps each supported --group-by value to the column
+// used to compute the group(s) of an issue
+var groupByColumns = map[string]string{
+	"status":    "status",
+	"assignee":  "assignee",
+	"priority":  "priority",
+	"component": "components",
+	"epic":      "epicLink",
+}
+
+// ValidateGroupBy returns an error if issues cannot be grouped by groupBy
+func ValidateGroupBy(groupBy string) error {
+	if _, ok := groupByColumns[strings.ToLower(groupBy)]; !ok {
+		return fmt.Errorf("cannot group by %q (supported: status, assignee, priority, component, epic)", groupBy)
+	}
+	return nil
+}
+
+// IssueGroup is a group of issues displayed with --group-by.
+type IssueGroup struct {
+	Name  string `json:"name" yaml:"name"`
+	Count int    `json:"count" yaml:"count"`
+	// Subtotals contains the sum of each numeric column (e.g. story points), keyed by column name
+	Subtotals map[string]float64 `json:"subtotals,omitempty" yaml:"subtotals,omitempty"`
+	// Keys are the keys of the issues in the group
+	Keys []string `json:"keys" yaml:"keys"`
+
+	// issues are the indexes in IssueList.Issues of the issues in this group
+	issues []int
+}
+
+// sortIssues sorts issues by keys, whose columns are sortColumns.
+// Sort is stable, so issues with same values keep the order returned by Jira.
+func sortIssues(issues []jira.Issue, keys []SortKey, sortColumns []Column) {
+	values := make(map[string][]string, len(issues))
+	for i := range issues {
+		v := make([]string, len(sortColumns))
+		for j := range sortColumns {
+			v[j] = sortColumns[j].SortValue(&issues[i])
+		}
+		values[issues[i].Key] = v
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		vi, vj := values[issues[i].Key], values[issues[j].Key]
+		for k := range keys {
+			c := compareValues(vi[k], vj[k])
+			if c == 0 {
+				continue
+			}
+			// empty values come last in both orders
+			if keys[k].Descending && vi[k] != "" && vj[k] != "" {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// compareValues compares a and b numerically if both are numbers,
+// alphabetically (case insensitive) otherwise. Empty values come last.
+func compareValues(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// groupIssues groups list issues by groupColumn. Groups are ordered by
+// first appearance, so sorting by the same column controls groups order.
+// An issue with multiple values (e.g. components) belongs to multiple groups.
+func groupIssues(list *IssueList, groupColumn *Column) []IssueGroup {
+	groups := make([]IssueGroup, 0)
+	index := make(map[string]int)
+	for i := range list.Issues {
+		names := groupNames(list.Issues[i].Issue, groupColumn)
+		for _, name := range names {
+			g, ok := index[name]
+			if !ok {
+				g = len(groups)
+				index[name] = g
+				groups = append(groups, IssueGroup{Name: name, Keys: make([]string, 0)})
+			}
+			groups[g].Count++
+			groups[g].Keys = append(groups[g].Keys, list.Issues[i].Key)
+			groups[g].issues = append(groups[g].issues, i)
+		}
+	}
+
+	for i := range groups {
+		groups[i].Subtotals = subtotals(list, groups[i].issues)
+	}
+	return groups
+}
+
+// groupNames returns the names of the groups issue belongs to
+func groupNames(issue *jira.Issue, groupColumn *Column) []string {
+	if groupColumn.FieldID == "components" {
+		names := make([]string, 0)
+		if issue.Fields != nil {
+			for _, c := range issue.Fields.Components {
+				names = append(names, c.Name)
+			}
+		}
+		if len(names) == 0 {
+			names = append(names, noGroup)
+		}
+		return names
+	}
+
+	name := groupColumn.Value(issue)
+	if name == "" {
+		name = noGroup
+	}
+	return []string{name}
+}
+
+// subtotals returns the sum of each numeric displayed column for issues
+// with passed indexes. A column is numeric if all its non empty values are numbers.
+func subtotals(list *IssueList, issues []int) map[string]float64 {
+	var result map[string]float64
+	for c := range list.columns {
+		if list.columns[c].FieldID == "key" {
+			continue
+		}
+		sum := 0.0
+		numeric := false
+		for _, i := range issues {
+			cell := list.Issues[i].cells[c]
+			if cell == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				numeric = false
+				break
+			}
+			sum += v
+			numeric = true
+		}
+		if numeric {
+			if result == nil {
+				result = make(map[string]float64)
+			}
+			result[list.columns[c].Name] = sum
+		}
+	}
+	return result
+}
+
+// formatNumber returns f without trailing zeros
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
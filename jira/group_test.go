@@ -0,0 +1,242 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// pointsField is the ID of the Story Points custom field of testIssues
+const pointsField = "customfield_10002"
+
+// testIssues returns issues with status, assignee, priority, components,
+// story points and last update set, updated 1, 5, 2 and 10 days before now
+func testIssues(now time.Time) []jira.Issue {
+	updated := func(days int) jira.Time { return jira.Time(now.AddDate(0, 0, -days)) }
+	return []jira.Issue{
+		{Key: "A-1", Fields: &jira.IssueFields{Status: &jira.Status{Name: "In Progress"},
+			Assignee: &jira.User{Name: "alice"}, Priority: &jira.Priority{ID: "2", Name: "High"},
+			Components: []*jira.Component{{Name: "API"}}, Updated: updated(1),
+			Unknowns: map[string]interface{}{pointsField: 3.0}}},
+		{Key: "A-2", Fields: &jira.IssueFields{Status: &jira.Status{Name: "Backlog"},
+			Priority:   &jira.Priority{ID: "3", Name: "Medium"},
+			Components: []*jira.Component{{Name: "API"}, {Name: "UI"}}, Updated: updated(5),
+			Unknowns: map[string]interface{}{pointsField: 5.0}}},
+		{Key: "A-3", Fields: &jira.IssueFields{Status: &jira.Status{Name: "in progress"},
+			Assignee: &jira.User{Name: "bob"}, Priority: &jira.Priority{ID: "1", Name: "Highest"},
+			Updated: updated(2)}},
+		{Key: "A-4", Fields: &jira.IssueFields{Status: &jira.Status{Name: "Backlog"},
+			Assignee: &jira.User{Name: "alice"}, Components: []*jira.Component{{Name: "UI"}}, Updated: updated(10),
+			Unknowns: map[string]interface{}{pointsField: 2.5}}},
+	}
+}
+
+func testResolveColumns(t *testing.T, names []string) []Column {
+	t.Helper()
+	columns, err := resolveColumns(names, func() ([]jira.Field, error) {
+		return []jira.Field{{ID: pointsField, Name: storyPointsField, Custom: true}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return columns
+}
+
+func keys(issues []jira.Issue) []string {
+	result := make([]string, len(issues))
+	for i := range issues {
+		result[i] = issues[i].Key
+	}
+	return result
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1", "1", 0},
+		{"9", "10", -1},
+		{"10", "9", 1},
+		{"2.5", "2.50", 0},
+		{"-5", "-10", 1},
+		{"Backlog", "in progress", -1},
+		{"In Progress", "in progress", 0},
+		// mixed numbers and strings compare as strings
+		{"10", "9a", -1},
+		{"Sprint-9", "Sprint-10", 1},
+		{"", "a", 1},
+		{"a", "", -1},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		if got := compareValues(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareValues(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseSortKeys(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    []SortKey
+		wantErr bool
+	}{
+		{s: "", want: []SortKey{}},
+		{s: "priority", want: []SortKey{{Column: "priority"}}},
+		{s: "priority, updated:DESC,key:asc", want: []SortKey{{Column: "priority"},
+			{Column: "updated", Descending: true}, {Column: "key"}}},
+		{s: "priority:down", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSortKeys(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSortKeys(%q): unexpected error %v", tt.s, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSortKeys(%q) = %+v, want %+v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		// priority IDs go from the highest priority, issues with no priority come last
+		{sortBy: "priority", want: []string{"A-3", "A-1", "A-2", "A-4"}},
+		{sortBy: "priority:desc", want: []string{"A-2", "A-1", "A-3", "A-4"}},
+		// most recently updated first
+		{sortBy: "updated", want: []string{"A-1", "A-3", "A-2", "A-4"}},
+		{sortBy: "updated:desc", want: []string{"A-4", "A-2", "A-3", "A-1"}},
+		// story points sort numerically, 2.5 before 3
+		{sortBy: "storyPoints", want: []string{"A-4", "A-1", "A-2", "A-3"}},
+		{sortBy: "storyPoints:desc", want: []string{"A-2", "A-1", "A-4", "A-3"}},
+		// status is case insensitive, so A-1 and A-3 are sorted by update
+		{sortBy: "status,updated:desc", want: []string{"A-4", "A-2", "A-3", "A-1"}},
+		{sortBy: "assignee,key:desc", want: []string{"A-4", "A-1", "A-3", "A-2"}},
+		// sort is stable
+		{sortBy: "type", want: []string{"A-1", "A-2", "A-3", "A-4"}},
+	}
+
+	now := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			sortBy, err := ParseSortKeys(tt.sortBy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, len(sortBy))
+			for i := range sortBy {
+				names[i] = sortBy[i].Column
+			}
+			issues := testIssues(now)
+			for i := range issues {
+				issues[i].Fields.Type = jira.IssueType{Name: "Bug"}
+			}
+			sortIssues(issues, sortBy, testResolveColumns(t, names))
+			if got := keys(issues); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupIssues(t *testing.T) {
+	tests := []struct {
+		groupBy string
+		sortBy  string
+		want    []IssueGroup
+	}{
+		{
+			groupBy: "status",
+			want: []IssueGroup{
+				{Name: "In Progress", Count: 1, Keys: []string{"A-1"}, Subtotals: map[string]float64{"storyPoints": 3}},
+				{Name: "Backlog", Count: 2, Keys: []string{"A-2", "A-4"}, Subtotals: map[string]float64{"storyPoints": 7.5}},
+				// no story points: no subtotal
+				{Name: "in progress", Count: 1, Keys: []string{"A-3"}},
+			},
+		},
+		{
+			// groups follow the sort order
+			groupBy: "assignee",
+			sortBy:  "assignee",
+			want: []IssueGroup{
+				{Name: "alice", Count: 2, Keys: []string{"A-1", "A-4"}, Subtotals: map[string]float64{"storyPoints": 5.5}},
+				{Name: "bob", Count: 1, Keys: []string{"A-3"}},
+				{Name: noGroup, Count: 1, Keys: []string{"A-2"}, Subtotals: map[string]float64{"storyPoints": 5}},
+			},
+		},
+		{
+			groupBy: "priority",
+			want: []IssueGroup{
+				{Name: "High", Count: 1, Keys: []string{"A-1"}, Subtotals: map[string]float64{"storyPoints": 3}},
+				{Name: "Medium", Count: 1, Keys: []string{"A-2"}, Subtotals: map[string]float64{"storyPoints": 5}},
+				{Name: "Highest", Count: 1, Keys: []string{"A-3"}},
+				{Name: noGroup, Count: 1, Keys: []string{"A-4"}, Subtotals: map[string]float64{"storyPoints": 2.5}},
+			},
+		},
+		{
+			// an issue with multiple components is in each group
+			groupBy: "component",
+			want: []IssueGroup{
+				{Name: "API", Count: 2, Keys: []string{"A-1", "A-2"}, Subtotals: map[string]float64{"storyPoints": 8}},
+				{Name: "UI", Count: 2, Keys: []string{"A-2", "A-4"}, Subtotals: map[string]float64{"storyPoints": 7.5}},
+				{Name: noGroup, Count: 1, Keys: []string{"A-3"}},
+			},
+		},
+	}
+
+	now := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			if err := ValidateGroupBy(tt.groupBy); err != nil {
+				t.Fatal(err)
+			}
+			issues := testIssues(now)
+			if tt.sortBy != "" {
+				sortBy, err := ParseSortKeys(tt.sortBy)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sortIssues(issues, sortBy, testResolveColumns(t, []string{tt.sortBy}))
+			}
+
+			// key is not summed, status is not numeric
+			columns := testResolveColumns(t, []string{"key", "status", "storyPoints"})
+			list := &IssueList{Count: len(issues), Issues: make([]IssueRecord, len(issues)), columns: columns}
+			for i := range issues {
+				list.Issues[i] = newIssueRecord(&issues[i], "", columns, true)
+			}
+			groupColumn := testResolveColumns(t, []string{groupByColumns[tt.groupBy]})[0]
+
+			got := groupIssues(list, &groupColumn)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d groups %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				got[i].issues = nil
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("group %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	for _, groupBy := range []string{"status", "Assignee", "priority", "component", "epic"} {
+		if err := ValidateGroupBy(groupBy); err != nil {
+			t.Errorf("ValidateGroupBy(%q): unexpected error %v", groupBy, err)
+		}
+	}
+	if err := ValidateGroupBy("reporter"); err == nil {
+		t.Error("expected an error grouping by reporter")
+	}
+}
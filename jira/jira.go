@@ -2,15 +2,18 @@ package jira
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/andygrunwald/go-jira"
-	"github.com/fatih/color"
 	"github.com/go-logr/logr"
-	"github.com/olekukonko/tablewriter"
 )
 
 const (
@@ -24,188 +27,249 @@ const (
 	username = "JIRA_USERNAME"
 	// password is the name of the env variable with the password (base64 encoded)
 	password = "JIRA_PASSWORD"
+	// jiraAuth is the name of the env variable with the auth type (basic, bearer, cloud or cookie)
+	jiraAuth = "JIRA_AUTH"
+	// jiraToken is the name of the env variable with the personal access token, API token or session cookie
+	jiraToken = "JIRA_TOKEN"
+	// jiraOAuthClientID is the name of the env variable with the OAuth application client ID
+	jiraOAuthClientID = "JIRA_OAUTH_CLIENT_ID"
+	// jiraOAuthClientSecret is the name of the env variable with the OAuth application client secret
+	jiraOAuthClientSecret = "JIRA_OAUTH_CLIENT_SECRET"
+	// jiraOAuthPrivateKey is the name of the env variable with the path of the private key signing OAuth 1.0a requests
+	jiraOAuthPrivateKey = "JIRA_OAUTH_PRIVATE_KEY"
+	// jiraMaxRetries, jiraRequestTimeout and jiraRequestBudget are the names of the env
+	// variables controlling how requests are retried (see RetryOptions)
+	jiraMaxRetries     = "JIRA_MAX_RETRIES"
+	jiraRequestTimeout = "JIRA_REQUEST_TIMEOUT"
+	jiraRequestBudget  = "JIRA_REQUEST_BUDGET"
+	// jiraCacheTTL is the name of the env variable overriding how long responses are cached
+	jiraCacheTTL = "JIRA_CACHE_TTL"
+	// jiraWarnAfter is the name of the env variable with the default of --warn-after
+	jiraWarnAfter = "JIRA_WARN_AFTER"
+	// jiraSecretStore is the name of the env variable with the secret store
+	// (helper:<command>, file[:<path>] or vault[:<path>])
+	jiraSecretStore = "JIRA_SECRET_STORE"
+	// jiraProfile is the name of the env variable with the config file profile to use
+	jiraProfile = "JIRA_PROFILE"
 )
 
-// VerifyEnvVariables verifies all needed environment variables are set
-func VerifyEnvVariables(logger logr.Logger) {
-	logger.Info("Verifying all needed environment variables are set")
-
-	logger.V(5).Info(fmt.Sprintf("Verify %s (this must contain the jira base url)", jiraBaseURL))
-	if _, ok := os.LookupEnv(jiraBaseURL); !ok {
-		logger.Info(fmt.Sprintf("Env variable %s not found.", jiraBaseURL))
-		panic(1)
-	}
-
-	logger.V(5).Info(fmt.Sprintf("Verify %s (this must contain the jira project name)", jiraProject))
-	if _, ok := os.LookupEnv(jiraProject); !ok {
-		logger.Info(fmt.Sprintf("Env variable %s not found.", jiraProject))
-		panic(1)
-	}
-
-	logger.V(5).Info(fmt.Sprintf("Verify %s (this must contain the board name)", jiraBoardName))
-	if _, ok := os.LookupEnv(jiraBoardName); !ok {
-		logger.Info(fmt.Sprintf("Env variable %s not found.", jiraBoardName))
-		panic(1)
-	}
+const (
+	// defaultPageSize is the number of issues fetched per search request
+	// (same as Jira default maxResults)
+	defaultPageSize = 50
+)
 
-	logger.V(5).Info(fmt.Sprintf("Verify %s (this must contain the username)", username))
-	if _, ok := os.LookupEnv(username); !ok {
-		logger.Info(fmt.Sprintf("Env variable %s not found.", username))
-		panic(1)
+// GetJiraClient returns a new Jira API client authenticating with credentials.
+// Failed requests are retried as defined by GetRetryOptions. Projects, boards
+// and sprints are cached on disk (see ResponseCache), unless DisableCache was called.
+func GetJiraClient(ctx context.Context, credentials *Credentials, logger logr.Logger) (JiraAPI, error) {
+	baseURL, _ := LookupSetting(BaseURLSetting)
+	if baseURL == "" {
+		msg := fmt.Sprintf("Jira base URL not set (env variable %s or %s in config profile).", jiraBaseURL, BaseURLSetting)
+		logger.Info(msg)
+		return nil, fmt.Errorf("%w: %s", ErrMissingConfig, msg)
 	}
 
-	logger.V(5).Info(fmt.Sprintf("Verify %s (this must password, base64 encoded)", password))
-	if _, ok := os.LookupEnv(password); !ok {
-		logger.Info(fmt.Sprintf("Env variable %s not found.", password))
-		panic(1)
+	retryOptions, err := GetRetryOptions()
+	if err != nil {
+		return nil, err
 	}
-}
-
-// GetJiraClient returns a new Jira API client.
-func GetJiraClient(ctx context.Context, username, password string, logger logr.Logger) (*jira.Client, error) {
-	tp := jira.BasicAuthTransport{
-		Username: username,
-		Password: password,
+	cache, err := GetResponseCache(baseURL)
+	if err != nil {
+		return nil, err
 	}
 
-	baseURL, ok := os.LookupEnv(jiraBaseURL)
-	if !ok {
-		msg := fmt.Sprintf("Env variable %s not found.", jiraBaseURL)
-		logger.Info(msg)
-		return nil, fmt.Errorf("%s", msg)
+	apiURL := baseURL
+	if credentials.OAuthToken != nil && credentials.OAuthToken.APIURL != "" {
+		apiURL = credentials.OAuthToken.APIURL
 	}
 
-	jiraClient, err := jira.NewClient(tp.Client(), baseURL)
+	transport := newCacheTransport(cache, newRetryTransport(http.DefaultTransport, retryOptions, logger), logger)
+	jiraClient, err := jira.NewClient(credentials.httpClient(baseURL, transport), apiURL)
 	if err != nil {
 		logger.Info(fmt.Sprintf("Failed to get jira client. Err: %v", err))
 		return nil, err
 	}
 
-	return jiraClient, nil
+	return NewJiraAPI(jiraClient), nil
 }
 
-// GetJiraProject returns the jira.Project with name projectName
-func GetJiraProject(ctxt context.Context, jiraClient *jira.Client, projectName string, logger logr.Logger) (*jira.Project, error) {
+// GetJiraProject returns the jira.Project with name projectName.
+// If projectName is empty, project setting is used (see LookupSetting).
+// Returns an error matching ErrNotFound if the project does not exist.
+func GetJiraProject(ctx context.Context, jiraClient JiraAPI, projectName string, logger logr.Logger) (*jira.Project, error) {
 	if projectName == "" {
-		var ok bool
-		projectName, ok = os.LookupEnv(jiraProject)
-		if !ok || projectName == "" {
-			msg := fmt.Sprintf("ProjectName was not passed and env variable %s is not set", jiraProject)
+		projectName, _ = LookupSetting(ProjectSetting)
+		if projectName == "" {
+			msg := fmt.Sprintf("ProjectName was not passed and neither env variable %s nor %s in config profile is set",
+				jiraProject, ProjectSetting)
 			logger.Info(msg)
-			return nil, fmt.Errorf("%s", msg)
+			return nil, fmt.Errorf("%w: %s", ErrMissingConfig, msg)
 		}
 	}
 
-	url := fmt.Sprintf("rest/api/2/project/%s", projectName)
-	req, _ := jiraClient.NewRequest("GET", url, nil)
-	project := &jira.Project{}
-	if _, err := jiraClient.Do(req, project); err != nil {
+	project, err := jiraClient.GetProject(ctx, projectName)
+	if err != nil {
 		logger.Info(fmt.Sprintf("Failed to get project with name: %s. Error: %v", projectName, err))
-		return nil, err
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("project %s: %w", projectName, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get project %s: %w", projectName, err)
 	}
 
 	return project, nil
 }
 
-// GetJiraBoard returns board with name boardName in project projectKey
-// returns the board if only one is found or an error if any occurs.
-// Returns nil if no board is found or more than one is found
-func GetJiraBoard(ctx context.Context, jiraClient *jira.Client, projectKey, boardName string, logger logr.Logger) (*jira.Board, error) {
+// GetJiraBoard returns board with name boardName in project projectKey.
+// If boardName is empty, board setting is used (see LookupSetting).
+// A numeric boardName selects the board by ID. Otherwise a board whose name is
+// boardName (ignoring case) is preferred to boards whose name contains it.
+// Returns an error matching ErrNotFound if no board is found and an
+// AmbiguousBoardError, listing the candidates, if more than one is found
+func GetJiraBoard(ctx context.Context, jiraClient JiraAPI, projectKey, boardName string, logger logr.Logger) (*jira.Board, error) {
 	if boardName == "" {
-		var ok bool
-		boardName, ok = os.LookupEnv(jiraBoardName)
-		if !ok || boardName == "" {
-			msg := fmt.Sprintf("boardName was not passed and env variable %s is not set", jiraBoardName)
+		boardName, _ = LookupSetting(BoardSetting)
+		if boardName == "" {
+			msg := fmt.Sprintf("boardName was not passed and neither env variable %s nor %s in config profile is set",
+				jiraBoardName, BoardSetting)
 			logger.Info(msg)
-			return nil, fmt.Errorf("%s", msg)
+			return nil, fmt.Errorf("%w: %s", ErrMissingConfig, msg)
 		}
 	}
 
-	boardListOptions := &jira.BoardListOptions{ProjectKeyOrID: projectKey, Name: boardName}
-	boardList, _, err := jiraClient.Board.GetAllBoards(boardListOptions)
+	if id, err := strconv.Atoi(boardName); err == nil {
+		boards, err := FindJiraBoards(ctx, jiraClient, projectKey, nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		for i := range boards {
+			if boards[i].ID == id {
+				return &boards[i], nil
+			}
+		}
+		// not a board ID: a board name can be a number
+	}
+
+	boards, err := FindJiraBoards(ctx, jiraClient, projectKey, &BoardFilter{Name: boardName}, logger)
 	if err != nil {
-		logger.Info(fmt.Sprintf("Failed to get board list. Error %v", err))
 		return nil, err
 	}
 
-	if boardList.Values == nil {
+	exact := make([]jira.Board, 0)
+	for i := range boards {
+		if strings.EqualFold(boards[i].Name, boardName) {
+			exact = append(exact, boards[i])
+		}
+	}
+	if len(exact) > 0 {
+		boards = exact
+	}
+
+	if len(boards) == 0 {
 		logger.Info(fmt.Sprintf("Got not result for GetAllBoards with projectKey: %s and boardName: %s ", projectKey, boardName))
-		return nil, nil
+		return nil, fmt.Errorf("board %q in project %s: %w", boardName, projectKey, ErrNotFound)
 	}
 
-	if len(boardList.Values) != 1 {
+	if len(boards) != 1 {
 		logger.Info(fmt.Sprintf("Got more than one result for GetAllBoards with projectKey: %s and boardName: %s ", projectKey, boardName))
-		logger.Info(fmt.Sprintf("Result: %v", boardList.Values))
-		return nil, nil
+		logger.Info(fmt.Sprintf("Result: %v", boards))
+		return nil, &AmbiguousBoardError{Name: boardName, Project: projectKey, Candidates: boards}
 	}
 
-	return &boardList.Values[0], nil
+	return &boards[0], nil
 }
 
-// GetJiraActiveSprint returns the active sprint for passed in board
-// Returns active sprint if found or an error if any occurs.
-// If no sprint is currently active, returns nil
-func GetJiraActiveSprint(ctx context.Context, jiraClient *jira.Client, boardID string, logger logr.Logger) (*jira.Sprint, error) {
-	if jiraClient == nil {
-		msg := "jiraClient is nil"
-		logger.Info(msg)
-		return nil, fmt.Errorf(msg)
+// GetJiraActiveSprints returns the active sprints of passed in board: more than one
+// on boards with parallel sprints. Only active sprints are requested to Jira.
+// If no sprint is currently active, returns an error matching ErrNoActiveSprint
+func GetJiraActiveSprints(ctx context.Context, jiraClient JiraAPI, boardID string, logger logr.Logger) ([]jira.Sprint, error) {
+	sprints, err := GetJiraSprints(ctx, jiraClient, boardID, &SprintFilter{States: []string{SprintActive}}, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	sprints, _, err := jiraClient.Board.GetAllSprintsWithContext(ctx, boardID)
+	if len(sprints) == 0 {
+		return nil, fmt.Errorf("board %s: %w", boardID, ErrNoActiveSprint)
+	}
+	active := make([]jira.Sprint, len(sprints))
+	for i := range sprints {
+		active[i] = sprints[i].Sprint
+	}
+	return active, nil
+}
+
+// GetJiraActiveSprint returns the active sprint for passed in board
+// Returns active sprint if found or an error if any occurs.
+// If no sprint is currently active, returns an error matching ErrNoActiveSprint.
+// If more than one sprint is active, returns an error matching ErrAmbiguousSprint
+func GetJiraActiveSprint(ctx context.Context, jiraClient JiraAPI, boardID string, logger logr.Logger) (*jira.Sprint, error) {
+	sprints, err := GetJiraActiveSprints(ctx, jiraClient, boardID, logger)
 	if err != nil {
-		logger.Info(fmt.Sprintf("Failed to get board list. Error: %v", err))
 		return nil, err
 	}
 
-	var activeSprint *jira.Sprint
-	now := time.Now()
-	for i := range sprints {
-		if sprints[i].StartDate != nil && sprints[i].EndDate != nil {
-			if sprints[i].StartDate.Before(now) && sprints[i].EndDate.After(now) {
-				return &sprints[i], nil
-			} else if sprints[i].StartDate.Before(now) {
-				if activeSprint == nil {
-					activeSprint = &sprints[i]
-				} else if sprints[i].EndDate.After(*activeSprint.EndDate) {
-					activeSprint = &sprints[i]
-				}
-			}
+	if len(sprints) > 1 {
+		names := make([]string, len(sprints))
+		for i := range sprints {
+			names[i] = sprints[i].Name
 		}
+		logger.Info(fmt.Sprintf("Got more than one active sprint for board %s: %v", boardID, names))
+		return nil, fmt.Errorf("%w: %d sprints of board %s are active: %s", ErrAmbiguousSprint, len(sprints),
+			boardID, strings.Join(names, ", "))
 	}
+	return &sprints[0], nil
+}
 
-	return activeSprint, nil
+// SprintsJQL returns the JQL clause matching the issues of any of sprints, e.g. "sprint in (12, 14)"
+func SprintsJQL(sprints []jira.Sprint) string {
+	ids := make([]string, len(sprints))
+	for i := range sprints {
+		ids[i] = strconv.Itoa(sprints[i].ID)
+	}
+	return fmt.Sprintf("sprint in (%s)", strings.Join(ids, ", "))
 }
 
-// GetJiraSprints returns the specified sprint for passed in board
+// GetJiraSprints returns the sprints of passed in board selected by filter (all if nil).
+// Only sprints in filter States are requested to Jira.
 // Returns sprints or an error if any occurs.
-func GetJiraSprints(ctx context.Context, jiraClient *jira.Client, boardID string, logger logr.Logger) ([]jira.Sprint, error) {
+func GetJiraSprints(ctx context.Context, jiraClient JiraAPI, boardID string, filter *SprintFilter,
+	logger logr.Logger) ([]Sprint, error) {
 	if jiraClient == nil {
 		msg := "jiraClient is nil"
 		logger.Info(msg)
 		return nil, fmt.Errorf(msg)
 	}
+	if filter == nil {
+		filter = &SprintFilter{}
+	}
 
-	sprints, _, err := jiraClient.Board.GetAllSprintsWithContext(ctx, boardID)
+	sprints, err := jiraClient.GetAllSprints(ctx, boardID,
+		&jira.GetAllSprintsOptions{State: strings.Join(filter.States, ",")})
 	if err != nil {
 		logger.Info(fmt.Sprintf("Failed to get board list. Error: %v", err))
 		return nil, err
 	}
 
-	return sprints, nil
+	selected := make([]Sprint, 0, len(sprints))
+	for i := range sprints {
+		if filter.matches(&sprints[i]) {
+			selected = append(selected, sprints[i])
+		}
+	}
+	return selected, nil
 }
 
 // GetJiraSprint returns all sprints for passed in board
 // Returns sprint if found or an error if any occurs.
-// If no matching sprint is found, returns nil
-func GetJiraSprint(ctx context.Context, jiraClient *jira.Client, boardID, sprintName string, logger logr.Logger) (*jira.Sprint, error) {
+// If no matching sprint is found, returns an error matching ErrNotFound
+func GetJiraSprint(ctx context.Context, jiraClient JiraAPI, boardID, sprintName string, logger logr.Logger) (*jira.Sprint, error) {
 	if jiraClient == nil {
 		msg := "jiraClient is nil"
 		logger.Info(msg)
 		return nil, fmt.Errorf(msg)
 	}
 
-	sprints, _, err := jiraClient.Board.GetAllSprintsWithContext(ctx, boardID)
+	sprints, err := jiraClient.GetAllSprints(ctx, boardID, nil)
 	if err != nil {
 		logger.Info(fmt.Sprintf("Failed to get board list. Error: %v", err))
 		return nil, err
@@ -213,22 +277,95 @@ func GetJiraSprint(ctx context.Context, jiraClient *jira.Client, boardID, sprint
 
 	for i := range sprints {
 		if sprints[i].Name == sprintName {
-			return &sprints[i], nil
+			return &sprints[i].Sprint, nil
 		}
 	}
 
-	return nil, nil
+	return nil, fmt.Errorf("sprint %q in board %s: %w", sprintName, boardID, ErrNotFound)
+}
+
+// QueryOptions controls how search results are fetched
+type QueryOptions struct {
+	// PageSize is the number of issues requested per page.
+	// If not set, defaultPageSize is used.
+	PageSize int
+	// MaxIssues is the maximum number of issues returned.
+	// If not set, all matching issues are returned.
+	MaxIssues int
+	// Expand is passed as is to the search request (e.g. "changelog")
+	Expand string
+	// Fields, if set, are the only issue fields requested
+	Fields []string
 }
 
-// GetJiraIssues finds all issues matching passed jql
-func GetJiraIssues(ctx context.Context, jiraClient *jira.Client, jql string, logger logr.Logger) ([]jira.Issue, error) {
-	issues, _, err := jiraClient.Issue.SearchWithContext(ctx, jql, nil)
+// ForEachJiraIssue calls f for every issue matching passed jql, fetching
+// one page at a time, so that very large result sets are never fully
+// kept in memory. Iteration stops at the first error returned by f.
+// Returns the total number of issues matching jql (which can be larger
+// than the number of issues passed to f if options.MaxIssues is set).
+func ForEachJiraIssue(ctx context.Context, jiraClient JiraAPI, jql string, options *QueryOptions,
+	f func(issue *jira.Issue) error, logger logr.Logger) (int, error) {
+	pageSize := defaultPageSize
+	maxIssues := 0
+	expand := ""
+	var fields []string
+	if options != nil {
+		if options.PageSize > 0 {
+			pageSize = options.PageSize
+		}
+		maxIssues = options.MaxIssues
+		expand = options.Expand
+		fields = options.Fields
+	}
+
+	count := 0
+	total := 0
+	for {
+		searchOptions := &jira.SearchOptions{StartAt: count, MaxResults: pageSize, Expand: expand, Fields: fields}
+		if maxIssues != 0 && maxIssues-count < pageSize {
+			searchOptions.MaxResults = maxIssues - count
+		}
+
+		logger.V(5).Info(fmt.Sprintf("Get issues matching jql:%s (startAt: %d, maxResults: %d)",
+			jql, searchOptions.StartAt, searchOptions.MaxResults))
+		issues, pageTotal, err := jiraClient.SearchIssues(ctx, jql, searchOptions)
+		if err != nil {
+			logger.Info(fmt.Sprintf("Failed to get all issues matching jql:%s. Error: %v", jql, err))
+			return 0, err
+		}
+		total = pageTotal
+
+		for i := range issues {
+			if err := f(&issues[i]); err != nil {
+				return total, err
+			}
+		}
+
+		// Server might return less than requested MaxResults,
+		// so always move forward by the number of issues received
+		count += len(issues)
+		if len(issues) == 0 || count >= total || (maxIssues != 0 && count >= maxIssues) {
+			return total, nil
+		}
+	}
+}
+
+// GetJiraIssues finds all issues matching passed jql.
+// Returns the issues and the total number of issues matching jql (which
+// is larger than the number of issues returned if options.MaxIssues is hit).
+func GetJiraIssues(ctx context.Context, jiraClient JiraAPI, jql string, options *QueryOptions,
+	logger logr.Logger) ([]jira.Issue, int, error) {
+	issues := make([]jira.Issue, 0)
+	total, err := ForEachJiraIssue(ctx, jiraClient, jql, options,
+		func(issue *jira.Issue) error {
+			issues = append(issues, *issue)
+			return nil
+		}, logger)
 	if err != nil {
-		logger.Info(fmt.Sprintf("Failed to get all issues matching jql:%s. Error: %v", jql, err))
-		return nil, err
+		return nil, 0, err
 	}
 
-	return issues, nil
+	return issues, total, nil
 }
 
 // CreateIssue creates new issue of type bug which will be added to sprint
@@ -236,7 +373,7 @@ func GetJiraIssues(ctx context.Context, jiraClient *jira.Client, jql string, log
 // - Assignee is the user the bug will be assigned to
 // - Reporter is the issue reporter
 // Return the issue Key or empty an error occurred.
-func CreateIssue(ctx context.Context, jiraClient *jira.Client, sprint *jira.Sprint, priority *jira.Priority,
+func CreateIssue(ctx context.Context, jiraClient JiraAPI, sprint *jira.Sprint, priority *jira.Priority,
 	projectKey, componentName, assignee, testName, summary string,
 	logger logr.Logger) (*jira.Issue, error) {
 	component := jira.Component{Name: componentName}
@@ -261,10 +398,9 @@ func CreateIssue(ctx context.Context, jiraClient *jira.Client, sprint *jira.Spri
 		},
 	}
 
-	issue, resp, err := jiraClient.Issue.CreateWithContext(ctx, &i)
+	issue, err := jiraClient.CreateIssue(ctx, &i)
 	if err != nil {
-		body, _ := io.ReadAll(resp.Body)
-		logger.Info(fmt.Sprintf("Failed to create issue. Error: %v. Resp %s", err, string(body)))
+		logger.Info(fmt.Sprintf("Failed to create issue. Error: %v", err))
 		return nil, err
 	}
 
@@ -274,25 +410,24 @@ func CreateIssue(ctx context.Context, jiraClient *jira.Client, sprint *jira.Spri
 }
 
 // AddCommentToIssue append comment to current open issue while also resetting sprint and priority.
-func AddCommentToIssue(ctx context.Context, jiraClient *jira.Client, issueID string,
+func AddCommentToIssue(ctx context.Context, jiraClient JiraAPI, issueID string,
 	commentMsg string, logger logr.Logger) error {
 	comment := jira.Comment{
 		Body: commentMsg,
 	}
 
-	if _, resp, err := jiraClient.Issue.AddCommentWithContext(ctx, issueID, &comment); err != nil {
-		body, _ := io.ReadAll(resp.Body)
-		logger.Info(fmt.Sprintf("Failed to update issue %s. Error: %v. Resp %s", issueID, err, string(body)))
+	if _, err := jiraClient.AddComment(ctx, issueID, &comment); err != nil {
+		logger.Info(fmt.Sprintf("Failed to update issue %s. Error: %v", issueID, err))
 		return err
 	}
 
 	return nil
 }
 
-func MoveIssueToSprint(ctx context.Context, jiraClient *jira.Client, sprintID int, issueID string, logger logr.Logger) error {
-	if resp, err := jiraClient.Sprint.MoveIssuesToSprintWithContext(ctx, sprintID, []string{issueID}); err != nil {
-		body, _ := io.ReadAll(resp.Body)
-		logger.Info(fmt.Sprintf("Failed to update issue %s. Error: %v. Resp %s", issueID, err, string(body)))
+// MoveIssueToSprint moves issue issueID to sprint sprintID
+func MoveIssueToSprint(ctx context.Context, jiraClient JiraAPI, sprintID int, issueID string, logger logr.Logger) error {
+	if err := jiraClient.MoveIssuesToSprint(ctx, sprintID, []string{issueID}); err != nil {
+		logger.Info(fmt.Sprintf("Failed to update issue %s. Error: %v", issueID, err))
 		return err
 	}
 
@@ -301,7 +436,7 @@ func MoveIssueToSprint(ctx context.Context, jiraClient *jira.Client, sprintID in
 
 // ResolveIssue moves issues to resolved.
 // Known initial transition state are: scope; designed; start progress; planned; resolved; close
-func ResolveIssue(ctx context.Context, jiraClient *jira.Client, issue *jira.Issue, logger logr.Logger) error {
+func ResolveIssue(ctx context.Context, jiraClient JiraAPI, issue *jira.Issue, logger logr.Logger) error {
 	// 761 scope; 771 designed; 4 start progress; 711 planned; 5 resolved; 2 closed
 
 	transitionMap := make(map[string][]string)
@@ -318,7 +453,7 @@ func ResolveIssue(ctx context.Context, jiraClient *jira.Client, issue *jira.Issu
 	transitionMap["5"] = []string{"5"}
 
 	// Get current available transitions
-	currentTransitions, _, err := jiraClient.Issue.GetTransitions(issue.ID)
+	currentTransitions, err := jiraClient.GetTransitions(ctx, issue.ID)
 	if err != nil {
 		logger.Info(fmt.Sprintf("Failed to get transition for issue %s. Err: %v", issue.ID, err))
 		return err
@@ -342,12 +477,12 @@ func ResolveIssue(ctx context.Context, jiraClient *jira.Client, issue *jira.Issu
 
 	q := &jira.GetQueryOptions{}
 	for _, t := range transitions {
-		tmpIssue, _, err := jiraClient.Issue.Get(issue.ID, q)
+		tmpIssue, err := jiraClient.GetIssue(ctx, issue.ID, q)
 		if err != nil {
 			logger.Info(fmt.Sprintf("Failed to get issue %s. Err: %v", issue.ID, err))
 			return err
 		}
-		if _, err = jiraClient.Issue.DoTransitionWithContext(ctx, tmpIssue.ID, t); err != nil {
+		if err = jiraClient.DoTransition(ctx, tmpIssue.ID, t); err != nil {
 			logger.Info(fmt.Sprintf("Failed to move to transition %s for issue %s. Err: %v", t, issue.ID, err))
 			return err
 		}
@@ -356,90 +491,144 @@ func ResolveIssue(ctx context.Context, jiraClient *jira.Client, issue *jira.Issu
 	return nil
 }
 
-func DisplayJiraIssues(ctx context.Context, jiraClient *jira.Client, jql string, warnAfter int, logger logr.Logger) error {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"KEY", "SUMMARY", "STATUS", "LAST UPDATE", "ASSIGNEE"})
-	table.SetAutoWrapText(false)
-	table.SetRowLine(true)
+// DisplayOptions controls how results are displayed
+type DisplayOptions struct {
+	QueryOptions
+	// WarnAfter, if set, highlights issues in a status for longer than its threshold,
+	// and adds a WARN column explaining why
+	WarnAfter WarnThresholds
+	// Output is the output format. Table is used by default.
+	Output OutputFormat
+	// Writer is where results are written. os.Stdout is used by default.
+	Writer io.Writer
+	// Template, if set, is used to display results instead of Output (see ParseTemplate)
+	Template *template.Template
+	// Columns are the names of the columns displayed for each issue (see ResolveColumns).
+	// If not set, DefaultColumns are displayed.
+	Columns []string
+	// SortBy, if set, are the columns issues are sorted by. Otherwise order is the one returned by Jira.
+	SortBy []SortKey
+	// GroupBy, if set, groups issues by status, assignee, priority, component or epic
+	GroupBy string
+}
 
-	issues, err := GetJiraIssues(ctx, jiraClient, jql, logger)
-	if err != nil {
-		return err
+func (o *DisplayOptions) writer() io.Writer {
+	if o.Writer == nil {
+		return os.Stdout
 	}
+	return o.Writer
+}
 
-	if len(issues) == 0 {
-		logger.Info("No issue found")
+// DisplayJiraIssues displays all issues matching passed jql.
+// If options.WarnAfter is set, issues in a status for longer than its threshold are highlighted
+func DisplayJiraIssues(ctx context.Context, jiraClient JiraAPI, jql string, options *DisplayOptions,
+	logger logr.Logger) error {
+	if options == nil {
+		options = &DisplayOptions{}
 	}
 
-	for i := range issues {
-		key := issues[i].Key
-		warning := false
-		if warnAfter != 0 && shouldWarn(jiraClient, &issues[i], warnAfter) {
-			warning = true
-		}
-		var summary, status, username string
-		if issues[i].Fields != nil {
-			summary = issues[i].Fields.Summary
-			if issues[i].Fields.Status != nil {
-				status = issues[i].Fields.Status.Name
-			} else {
-				status = "N/A"
-			}
-			if issues[i].Fields.Assignee != nil {
-				username = issues[i].Fields.Assignee.Name
-			}
+	// fields metadata is fetched once for columns, sort and group columns
+	resolveColumns := newColumnResolver(ctx, jiraClient, logger)
+	columns := defaultColumns()
+	if len(options.Columns) > 0 {
+		var err error
+		columns, err = resolveColumns(options.Columns)
+		if err != nil {
+			return err
 		}
+	}
+
+	sortColumnNames := make([]string, len(options.SortBy))
+	for i := range options.SortBy {
+		sortColumnNames[i] = options.SortBy[i].Column
+	}
+	sortColumns, err := resolveColumns(sortColumnNames)
+	if err != nil {
+		return err
+	}
 
-		// lastUpdate := string([]byte(time.Time(issues[i].Fields.Updated).Format("\"2006-01-02T\"")))
-		lastUpdateTime := time.Time(issues[i].Fields.Updated)
-		diff := time.Since(lastUpdateTime)
-		lastUpdate := fmt.Sprintf("%d days", int(diff.Hours()/24))
-
-		if warning {
-			table.Append([]string{color.New(color.FgRed).Sprintf(key),
-				color.New(color.FgRed).Sprintf(summary), color.New(color.FgRed).Sprintf(status),
-				color.New(color.FgRed).Sprintf(lastUpdate), color.New(color.FgRed).Sprintf(username)})
-		} else {
-			table.Append([]string{key, summary, status, lastUpdate, username})
+	var groupColumn *Column
+	if options.GroupBy != "" {
+		if err := ValidateGroupBy(options.GroupBy); err != nil {
+			return err
+		}
+		groupColumns, err := resolveColumns([]string{groupByColumns[strings.ToLower(options.GroupBy)]})
+		if err != nil {
+			return err
 		}
+		groupColumn = &groupColumns[0]
 	}
 
-	table.Render()
-	return nil
-}
+	now := time.Now()
+	if len(options.WarnAfter) > 0 {
+		columns = append(columns, warnColumn(options.WarnAfter, now))
+	}
 
-func shouldWarn(jiraClient *jira.Client, issue *jira.Issue, warnAfter int) bool {
-	var cIssue *jira.Issue
-	var err error
-	if issue.Fields.Status != nil &&
-		issue.Fields.Status.Name == "In Progress" {
-		cIssue, _, err = jiraClient.Sprint.GetIssue(issue.ID, &jira.GetQueryOptions{Expand: "changelog"})
-		if err != nil {
-			return false
+	queryOptions := options.QueryOptions
+	if options.Template == nil && len(queryOptions.Fields) == 0 {
+		// Templates can access any field. Otherwise request only the needed ones.
+		neededColumns := append(append([]Column{}, columns...), sortColumns...)
+		if groupColumn != nil {
+			neededColumns = append(neededColumns, *groupColumn)
 		}
-	} else {
-		return false
+		queryOptions.Fields = issueFields(neededColumns)
+	}
+	if len(options.WarnAfter) > 0 {
+		// changelogs come with the search results, instead of one request per issue
+		queryOptions.Expand = addExpand(queryOptions.Expand, "changelog")
 	}
 
-	if cIssue.Changelog == nil {
-		return false
+	issues, total, err := GetJiraIssues(ctx, jiraClient, jql, &queryOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	if len(issues) == 0 {
+		logger.Info("No issue found")
 	}
 
-	hours := time.Duration(-24 * warnAfter)
+	if len(options.SortBy) > 0 {
+		sortIssues(issues, options.SortBy, sortColumns)
+	}
 
-	for i := range cIssue.Changelog.Histories {
-		history := cIssue.Changelog.Histories[i]
-		historyTime, err := history.CreatedTime()
-		if err != nil {
-			continue
+	if len(options.WarnAfter) > 0 {
+		if err := fetchChangelogs(ctx, jiraClient, issues, options.WarnAfter.applies, logger); err != nil {
+			return err
 		}
-		for j := range history.Items {
-			logItem := history.Items[j]
-			if logItem.ToString == "In Progress" &&
-				historyTime.Before(time.Now().Add(-hours*time.Hour)) {
-				return true
+	}
+
+	list := &IssueList{Total: total, Count: len(issues), Issues: make([]IssueRecord, len(issues)),
+		columns: columns, customColumns: len(options.Columns) > 0, groupBy: options.GroupBy}
+	for i := range issues {
+		list.Issues[i] = newIssueRecord(&issues[i], options.WarnAfter.Reason(&issues[i], now), columns,
+			list.customColumns)
+	}
+
+	if groupColumn != nil {
+		list.Groups = groupIssues(list, groupColumn)
+	}
+
+	if options.Template != nil {
+		return options.Template.Execute(options.writer(), list)
+	}
+	return writeIssues(options.writer(), options.Output, list)
+}
+
+// issueFields returns the issue fields needed to display columns
+// (and the fields of IssueRecord)
+func issueFields(columns []Column) []string {
+	fields := []string{"summary", "status", "assignee", "updated"}
+	for i := range columns {
+		found := columns[i].FieldID == "key"
+		for j := range fields {
+			if fields[j] == columns[i].FieldID {
+				found = true
+				break
 			}
 		}
+		if !found {
+			fields = append(fields, columns[i].FieldID)
+		}
 	}
-	return false
+	return fields
 }
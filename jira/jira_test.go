@@ -0,0 +1,102 @@
+package jira_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+
+	jirautils "github.com/gianlucam76/jira_utils/jira"
+	"github.com/gianlucam76/jira_utils/jira/fake"
+)
+
+// pagedClient is a fake client returning at most maxPage issues per search, as Jira does
+// when maxResults is above its limit, and recording the requested pages
+type pagedClient struct {
+	*fake.Client
+	maxPage  int
+	requests []jira.SearchOptions
+}
+
+func (c *pagedClient) SearchIssues(ctx context.Context, jql string, options *jira.SearchOptions) ([]jira.Issue, int, error) {
+	c.requests = append(c.requests, *options)
+	capped := *options
+	if c.maxPage > 0 && (capped.MaxResults == 0 || capped.MaxResults > c.maxPage) {
+		capped.MaxResults = c.maxPage
+	}
+	return c.Client.SearchIssues(ctx, jql, &capped)
+}
+
+// newPagedClient returns a pagedClient with n issues in project CLOUDSTACK
+func newPagedClient(n, maxPage int) *pagedClient {
+	client := fake.NewClient()
+	for i := 1; i <= n; i++ {
+		client.Issues = append(client.Issues, jira.Issue{ID: fmt.Sprintf("%d", 20000+i),
+			Key: fmt.Sprintf("CLOUDSTACK-%d", i), Fields: &jira.IssueFields{Project: jira.Project{Key: "CLOUDSTACK"}}})
+	}
+	return &pagedClient{Client: client, maxPage: maxPage}
+}
+
+func TestGetJiraIssuesPagination(t *testing.T) {
+	tests := []struct {
+		name    string
+		issues  int
+		maxPage int
+		options *jirautils.QueryOptions
+		// wantPages are the startAt and maxResults of each request
+		wantPages  [][2]int
+		wantIssues int
+	}{
+		{name: "no issues", issues: 0, wantPages: [][2]int{{0, 50}}, wantIssues: 0},
+		{name: "one page", issues: 30, wantPages: [][2]int{{0, 50}}, wantIssues: 30},
+		{name: "several pages", issues: 120, wantPages: [][2]int{{0, 50}, {50, 50}, {100, 50}}, wantIssues: 120},
+		{name: "page size", issues: 25, options: &jirautils.QueryOptions{PageSize: 10},
+			wantPages: [][2]int{{0, 10}, {10, 10}, {20, 10}}, wantIssues: 25},
+		{name: "max issues", issues: 120, options: &jirautils.QueryOptions{PageSize: 50, MaxIssues: 70},
+			wantPages: [][2]int{{0, 50}, {50, 20}}, wantIssues: 70},
+		{name: "max issues above total", issues: 20, options: &jirautils.QueryOptions{MaxIssues: 100},
+			wantPages: [][2]int{{0, 50}}, wantIssues: 20},
+		{name: "server returns smaller pages", issues: 45, maxPage: 20,
+			wantPages: [][2]int{{0, 50}, {20, 50}, {40, 50}}, wantIssues: 45},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newPagedClient(tt.issues, tt.maxPage)
+			issues, total, err := jirautils.GetJiraIssues(context.TODO(), client, "project = CLOUDSTACK", tt.options,
+				logr.Discard())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if total != tt.issues {
+				t.Errorf("got total %d, want %d", total, tt.issues)
+			}
+			if len(issues) != tt.wantIssues {
+				t.Fatalf("got %d issues, want %d", len(issues), tt.wantIssues)
+			}
+			for i := range issues {
+				if want := fmt.Sprintf("CLOUDSTACK-%d", i+1); issues[i].Key != want {
+					t.Fatalf("issue %d: got %s, want %s", i, issues[i].Key, want)
+				}
+			}
+
+			pages := make([][2]int, len(client.requests))
+			for i := range client.requests {
+				pages[i] = [2]int{client.requests[i].StartAt, client.requests[i].MaxResults}
+			}
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("got pages %v, want %v", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestGetJiraIssuesError(t *testing.T) {
+	client := newPagedClient(10, 0)
+	if _, _, err := jirautils.GetJiraIssues(context.TODO(), client, "summary ~ registry", nil, logr.Discard()); err == nil {
+		t.Error("expected an error for unsupported JQL")
+	}
+}
@@ -0,0 +1,610 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// DefaultOAuthRedirectURL is the redirect URL used by OAuthLogin when none is passed.
+	// It must be registered as callback URL of the OAuth application in Jira.
+	DefaultOAuthRedirectURL = "http://localhost:8085/callback"
+
+	// cloudAuthURL, cloudTokenURL and cloudResourcesURL are the Atlassian Cloud OAuth 2.0 (3LO) endpoints
+	cloudAuthURL      = "https://auth.atlassian.com/authorize"
+	cloudTokenURL     = "https://auth.atlassian.com/oauth/token"
+	cloudResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	// cloudAPIURL is the URL Jira Cloud REST API is reached at with OAuth, for a given cloud ID
+	cloudAPIURL = "https://api.atlassian.com/ex/jira/%s"
+	// cloudScopes are the scopes requested to Jira Cloud (offline_access to get a refresh token)
+	cloudScopes = "read:jira-work write:jira-work read:jira-user offline_access"
+
+	// serverAuthPath and serverTokenPath are the Jira Data Center/Server (8.22+) OAuth 2.0 endpoints
+	serverAuthPath  = "rest/oauth2/latest/authorize"
+	serverTokenPath = "rest/oauth2/latest/token"
+	// serverScopes are the scopes requested to Jira Data Center/Server
+	serverScopes = "WRITE"
+
+	// tokenExpiryMargin is how long before its expiry an access token is refreshed
+	tokenExpiryMargin = time.Minute
+	// loginTimeout is how long OAuthLogin waits for the user to authorize access
+	loginTimeout = 5 * time.Minute
+)
+
+// ErrNotLoggedIn is returned when no OAuth token is cached for the Jira base URL in use
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// OAuthToken is an OAuth 2.0 token cached on disk by OAuthLogin
+type OAuthToken struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+	// APIURL is the URL REST API requests are sent to. It differs from the
+	// base URL for Jira Cloud (api.atlassian.com/ex/jira/<cloud id>).
+	APIURL string `json:"apiURL"`
+}
+
+// expired returns true if the access token is expired or about to expire
+func (t *OAuthToken) expired() bool {
+	return !t.Expiry.IsZero() && time.Now().Add(tokenExpiryMargin).After(t.Expiry)
+}
+
+// oauthEndpoints are the OAuth endpoints of a Jira instance
+type oauthEndpoints struct {
+	authURL  string
+	tokenURL string
+	scopes   string
+	// cloud is true for Jira Cloud, which uses Atlassian endpoints and
+	// requires the cloud ID of the site to reach the REST API
+	cloud bool
+}
+
+// getOAuthEndpoints returns the OAuth endpoints for Jira at baseURL
+func getOAuthEndpoints(baseURL string) (*oauthEndpoints, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		return &oauthEndpoints{authURL: cloudAuthURL, tokenURL: cloudTokenURL, scopes: cloudScopes, cloud: true}, nil
+	}
+	base := strings.TrimSuffix(baseURL, "/") + "/"
+	return &oauthEndpoints{authURL: base + serverAuthPath, tokenURL: base + serverTokenPath, scopes: serverScopes}, nil
+}
+
+// OAuthTokenPath returns the file caching the OAuth token for Jira at baseURL and the
+// profile in use (see UseProfile): a file in the oauth directory next to the config file
+func OAuthTokenPath(baseURL string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(baseURL, "/") + "\n" + ActiveProfile()))
+	return filepath.Join(dir, "oauth", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// LoadOAuthToken returns the OAuth token cached for Jira at baseURL.
+// Returns an error if no token is cached or the file is accessible by others.
+func LoadOAuthToken(baseURL string) (*OAuthToken, error) {
+	path, err := OAuthTokenPath(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPrivateFile(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w to %s: run 'jira-utils auth login'", ErrNotLoggedIn, baseURL)
+		}
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	token := &OAuthToken{}
+	if err := json.Unmarshal(content, token); err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth token file %s: %w", path, err)
+	}
+	return token, nil
+}
+
+// saveOAuthToken caches token for Jira at baseURL, in a file readable by the current user only
+func saveOAuthToken(baseURL string, token *OAuthToken) error {
+	path, err := OAuthTokenPath(baseURL)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(path, content)
+}
+
+// DeleteOAuthToken removes the OAuth token cached for Jira at baseURL, if any
+func DeleteOAuthToken(baseURL string) error {
+	path, err := OAuthTokenPath(baseURL)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// checkPrivateFile returns an error if file path does not exist or
+// is accessible by group or others
+func checkPrivateFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("%s has permissions %#o: it must not be accessible by group or others (chmod 600 %s)",
+			path, info.Mode().Perm(), path)
+	}
+	return nil
+}
+
+// writePrivateFile atomically writes content to file path, readable by the current user only
+func writePrivateFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// OAuthLogin performs the OAuth 2.0 authorization code flow against Jira at
+// baseURL: it asks the user (writing to w) to open the authorization page,
+// waits for Jira to redirect to redirectURL, exchanges the code for a token
+// and caches it (see LoadOAuthToken).
+func OAuthLogin(ctx context.Context, baseURL, clientID, clientSecret, redirectURL string, w io.Writer,
+	logger logr.Logger) (*OAuthToken, error) {
+	endpoints, err := getOAuthEndpoints(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"client_id":     {clientID},
+		"redirect_uri":  {redirectURL},
+		"response_type": {"code"},
+		"scope":         {endpoints.scopes},
+		"state":         {state},
+	}
+	if endpoints.cloud {
+		params.Set("audience", "api.atlassian.com")
+		params.Set("prompt", "consent")
+	} else {
+		challenge := sha256.Sum256([]byte(verifier))
+		params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+		params.Set("code_challenge_method", "S256")
+	}
+
+	query, err := waitForCallback(ctx, endpoints.authURL+"?"+params.Encode(), redirectURL, w, func(query url.Values) error {
+		switch {
+		case query.Get("error") != "":
+			return fmt.Errorf("authorization denied: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("state") != state:
+			return fmt.Errorf("invalid OAuth state in callback")
+		case query.Get("code") == "":
+			return fmt.Errorf("no authorization code in callback")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokenParams := map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     clientID,
+		"client_secret": clientSecret,
+		"code":          query.Get("code"),
+		"redirect_uri":  redirectURL,
+	}
+	if !endpoints.cloud {
+		tokenParams["code_verifier"] = verifier
+	}
+	token, err := requestOAuthToken(ctx, endpoints, tokenParams)
+	if err != nil {
+		logger.Info(fmt.Sprintf("Failed to get OAuth token. Error: %v", err))
+		return nil, err
+	}
+
+	token.APIURL = baseURL
+	if endpoints.cloud {
+		token.APIURL, err = cloudAPIURLFor(ctx, baseURL, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := saveOAuthToken(baseURL, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// waitForCallback asks the user (writing to w) to open authURL and waits for Jira to redirect
+// to redirectURL once access is authorized. check validates the callback query parameters.
+func waitForCallback(ctx context.Context, authURL, redirectURL string, w io.Writer,
+	check func(query url.Values) error) (url.Values, error) {
+	redirect, err := url.Parse(redirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL %q: %w", redirectURL, err)
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for OAuth callback on %s: %w", redirect.Host, err)
+	}
+
+	type callbackResult struct {
+		query url.Values
+		err   error
+	}
+	results := make(chan callbackResult, 1)
+	var once sync.Once
+	server := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != redirect.Path {
+			http.NotFound(rw, r)
+			return
+		}
+		result := callbackResult{query: r.URL.Query()}
+		result.err = check(result.query)
+		if result.err != nil {
+			http.Error(rw, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(rw, "jira_utils is now authorized. You can close this window.")
+		}
+		once.Do(func() { results <- result })
+	})}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	fmt.Fprintf(w, "Open this URL in your browser to authorize jira_utils:\n\n%s\n\n", authURL)
+
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	select {
+	case result := <-results:
+		return result.query, result.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("OAuth login not completed: %w", ctx.Err())
+	}
+}
+
+// refreshOAuthToken gets a new access token for Jira at baseURL using the
+// refresh token in token, and caches it
+func refreshOAuthToken(ctx context.Context, baseURL, clientID, clientSecret string, token *OAuthToken) (*OAuthToken, error) {
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("OAuth token for %s expired and cannot be refreshed: run 'jira-utils auth login'", baseURL)
+	}
+	endpoints, err := getOAuthEndpoints(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed, err := requestOAuthToken(ctx, endpoints, map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     clientID,
+		"client_secret": clientSecret,
+		"refresh_token": token.RefreshToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh OAuth token for %s (run 'jira-utils auth login'): %w", baseURL, err)
+	}
+
+	// refresh token is not returned if not rotated
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+	refreshed.APIURL = token.APIURL
+	if err := saveOAuthToken(baseURL, refreshed); err != nil {
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// requestOAuthToken sends params to the token endpoint and returns the token.
+// Jira Cloud expects a JSON body, Jira Data Center/Server a form.
+func requestOAuthToken(ctx context.Context, endpoints *oauthEndpoints, params map[string]string) (*OAuthToken, error) {
+	var body io.Reader
+	contentType := "application/x-www-form-urlencoded"
+	if endpoints.cloud {
+		content, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(content)
+		contentType = "application/json"
+	} else {
+		form := url.Values{}
+		for k, v := range params {
+			form.Set(k, v)
+		}
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoints.tokenURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid response from %s (HTTP %d): %w", endpoints.tokenURL, resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || response.AccessToken == "" {
+		return nil, fmt.Errorf("token request rejected (HTTP %d): %s %s", resp.StatusCode, response.Error, response.ErrorDescription)
+	}
+
+	token := &OAuthToken{AccessToken: response.AccessToken, RefreshToken: response.RefreshToken}
+	if response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// cloudAPIURLFor returns the URL to reach the REST API of the Jira Cloud site at baseURL
+func cloudAPIURLFor(ctx context.Context, baseURL, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cloudResourcesURL, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var resources []struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return "", fmt.Errorf("failed to get accessible Jira Cloud sites: %w", err)
+	}
+	for i := range resources {
+		if strings.EqualFold(strings.TrimSuffix(resources[i].URL, "/"), strings.TrimSuffix(baseURL, "/")) {
+			return fmt.Sprintf(cloudAPIURL, resources[i].ID), nil
+		}
+	}
+	return "", fmt.Errorf("site %s is not accessible with the authorized OAuth token", baseURL)
+}
+
+// oauthTransport authenticates requests with the cached OAuth token,
+// refreshing it when expired. The client secret is read (see LookupSecret)
+// the first time the token is refreshed.
+type oauthTransport struct {
+	mu           sync.Mutex
+	baseURL      string
+	clientID     string
+	clientSecret string
+	token        *OAuthToken
+	// refreshing, if set, is closed when the refresh in progress ends
+	refreshing chan struct{}
+	transport  http.RoundTripper
+}
+
+func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	accessToken, err := t.accessToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	req2 := req.Clone(req.Context()) // per RoundTripper contract
+	req2.Header.Set("Authorization", "Bearer "+accessToken)
+	return t.transport.RoundTrip(req2)
+}
+
+// accessToken returns the access token, refreshing it if expired. The lock is not held
+// while refreshing: concurrent requests wait for the refresh in progress to end.
+func (t *oauthTransport) accessToken(ctx context.Context) (string, error) {
+	for {
+		t.mu.Lock()
+		if !t.token.expired() {
+			accessToken := t.token.AccessToken
+			t.mu.Unlock()
+			return accessToken, nil
+		}
+		if refreshing := t.refreshing; refreshing != nil {
+			t.mu.Unlock()
+			select {
+			case <-refreshing:
+				continue
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+		refreshing := make(chan struct{})
+		t.refreshing = refreshing
+		token, clientSecret := t.token, t.clientSecret
+		t.mu.Unlock()
+
+		var refreshed *OAuthToken
+		var err error
+		if clientSecret == "" {
+			clientSecret, err = LookupSecret(OAuthClientSecretSetting)
+		}
+		if err == nil {
+			refreshed, err = refreshOAuthToken(ctx, t.baseURL, t.clientID, clientSecret, token)
+		}
+
+		t.mu.Lock()
+		t.refreshing = nil
+		close(refreshing)
+		if err != nil {
+			t.mu.Unlock()
+			return "", err
+		}
+		t.clientSecret = clientSecret
+		t.token = refreshed
+		t.mu.Unlock()
+		return refreshed.AccessToken, nil
+	}
+}
+
+// randomString returns a random URL safe string, used as OAuth state and PKCE verifier
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// OAuthStatus describes the cached OAuth token for the base URL in use.
+type OAuthStatus struct {
+	BaseURL    string     `json:"baseURL" yaml:"baseURL"`
+	Auth       string     `json:"auth" yaml:"auth"`
+	TokenFile  string     `json:"tokenFile" yaml:"tokenFile"`
+	LoggedIn   bool       `json:"loggedIn" yaml:"loggedIn"`
+	Expiry     *time.Time `json:"expiry,omitempty" yaml:"expiry,omitempty"`
+	Expired    bool       `json:"expired" yaml:"expired"`
+	CanRefresh bool       `json:"canRefresh" yaml:"canRefresh"`
+	APIURL     string     `json:"apiURL,omitempty" yaml:"apiURL,omitempty"`
+	// Error is set if the token file cannot be used (e.g. wrong permissions)
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// GetOAuthStatus returns the status of the OAuth token cached for the base URL in use
+func GetOAuthStatus() (*OAuthStatus, error) {
+	if err := VerifySettings(BaseURLSetting); err != nil {
+		return nil, err
+	}
+	baseURL, _ := LookupSetting(BaseURLSetting)
+	authName, _ := LookupSetting(AuthSetting)
+	authType, err := ParseAuthType(authName)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := OAuthTokenPath(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	status := &OAuthStatus{BaseURL: baseURL, Auth: string(authType), TokenFile: path}
+
+	token, err := LoadOAuthToken(baseURL)
+	if err != nil {
+		if !errors.Is(err, ErrNotLoggedIn) {
+			status.Error = err.Error()
+		}
+		return status, nil
+	}
+	status.LoggedIn = true
+	if !token.Expiry.IsZero() {
+		status.Expiry = &token.Expiry
+	}
+	status.Expired = token.expired()
+	status.CanRefresh = token.RefreshToken != ""
+	status.APIURL = token.APIURL
+	return status, nil
+}
+
+// DisplayOAuthStatus writes the status of the OAuth token cached for the base URL in use
+func DisplayOAuthStatus(w io.Writer, format OutputFormat) error {
+	status, err := GetOAuthStatus()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, status)
+	case OutputTable, "":
+	default:
+		return fmt.Errorf("output format %s is not supported for auth status (supported: table, json, yaml)", format)
+	}
+
+	state := "not logged in"
+	switch {
+	case status.Error != "":
+		state = "error: " + status.Error
+	case status.LoggedIn && status.Expired && status.CanRefresh:
+		state = "logged in (access token expired, will be refreshed)"
+	case status.LoggedIn && status.Expired:
+		state = "logged in (access token expired, login again)"
+	case status.LoggedIn:
+		state = "logged in"
+	}
+	expiry := ""
+	if status.Expiry != nil {
+		expiry = status.Expiry.Local().Format(time.RFC1123)
+	}
+
+	table := newTable(w, []string{"BASE URL", "AUTH", "STATUS", "EXPIRY", "TOKEN FILE"})
+	table.SetAutoWrapText(false)
+	table.Append([]string{status.BaseURL, status.Auth, state, expiry, status.TokenFile})
+	table.Render()
+	return nil
+}
@@ -0,0 +1,255 @@
+package jira
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// oauth1RequestTokenPath, oauth1AuthorizePath and oauth1AccessTokenPath are the
+	// OAuth 1.0a endpoints of Jira Data Center/Server application links
+	oauth1RequestTokenPath = "plugins/servlet/oauth/request-token"
+	oauth1AuthorizePath    = "plugins/servlet/oauth/authorize"
+	oauth1AccessTokenPath  = "plugins/servlet/oauth/access-token"
+)
+
+// LoadOAuthPrivateKey reads the RSA private key (PEM encoded, PKCS #1 or PKCS #8) used to sign
+// OAuth 1.0a requests. Returns an error if the file is accessible by others.
+func LoadOAuthPrivateKey(path string) (*rsa.PrivateKey, error) {
+	if err := checkPrivateFile(path); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseOAuthPrivateKey(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// parseOAuthPrivateKey parses a PEM encoded RSA private key
+func parseOAuthPrivateKey(content []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// oauth1Signer signs requests (RFC 5849, RSA-SHA1) as the consumer of a Jira application link
+type oauth1Signer struct {
+	consumerKey string
+	privateKey  *rsa.PrivateKey
+}
+
+// authorization returns the Authorization header of signed req. params are the
+// protocol parameters specific to req, e.g. oauth_token.
+func (s *oauth1Signer) authorization(req *http.Request, params map[string]string) (string, error) {
+	nonce, err := randomString()
+	if err != nil {
+		return "", err
+	}
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     s.consumerKey,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": "RSA-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+	for k, v := range params {
+		oauthParams[k] = v
+	}
+
+	// RSA-SHA1 is the only signature method supported by Jira application links
+	sum := sha1.Sum([]byte(oauth1BaseString(req, oauthParams)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA1, sum[:])
+	if err != nil {
+		return "", err
+	}
+	oauthParams["oauth_signature"] = base64.StdEncoding.EncodeToString(signature)
+
+	names := make([]string, 0, len(oauthParams))
+	for k := range oauthParams {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, k := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, oauth1Escape(k), oauth1Escape(oauthParams[k]))
+	}
+	return "OAuth " + strings.Join(pairs, ", "), nil
+}
+
+// oauth1BaseString returns the signature base string of req: method, URL without query,
+// then query and protocol parameters sorted by name and value. Form bodies are not
+// signed: jira_utils sends JSON.
+func oauth1BaseString(req *http.Request, oauthParams map[string]string) string {
+	type param struct{ name, value string }
+	params := make([]param, 0, len(oauthParams))
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			params = append(params, param{oauth1Escape(name), oauth1Escape(value)})
+		}
+	}
+	for name, value := range oauthParams {
+		params = append(params, param{oauth1Escape(name), oauth1Escape(value)})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].name != params[j].name {
+			return params[i].name < params[j].name
+		}
+		return params[i].value < params[j].value
+	})
+	pairs := make([]string, len(params))
+	for i := range params {
+		pairs[i] = params[i].name + "=" + params[i].value
+	}
+
+	scheme, host := strings.ToLower(req.URL.Scheme), strings.ToLower(req.URL.Host)
+	host = strings.TrimSuffix(host, map[string]string{"http": ":80", "https": ":443"}[scheme])
+	baseURI := scheme + "://" + host + req.URL.EscapedPath()
+	return strings.ToUpper(req.Method) + "&" + oauth1Escape(baseURI) + "&" + oauth1Escape(strings.Join(pairs, "&"))
+}
+
+// oauth1Escape percent encodes s as required by RFC 5849: all but unreserved characters
+func oauth1Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// requestToken sends a signed request to the request-token or access-token
+// endpoint and returns the token in the form encoded response
+func (s *oauth1Signer) requestToken(ctx context.Context, endpoint string, params map[string]string) (url.Values, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	authorization, err := s.authorization(req, params)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	values, err := url.ParseQuery(string(body))
+	if resp.StatusCode != http.StatusOK || err != nil || values.Get("oauth_token") == "" {
+		// Jira reports the reason as oauth_problem, e.g. consumer_key_unknown or signature_invalid
+		problem := values.Get("oauth_problem")
+		if problem == "" {
+			problem = strings.TrimSpace(string(body))
+		}
+		return nil, fmt.Errorf("token request rejected (HTTP %d): %s", resp.StatusCode, problem)
+	}
+	return values, nil
+}
+
+// OAuth1Login performs the OAuth 1.0a flow of Jira Data Center/Server application links against
+// Jira at baseURL: it gets a request token signed with privateKey, asks the user (writing to w)
+// to authorize it, waits for Jira to redirect to redirectURL, exchanges the request token for
+// an access token and caches it (see LoadOAuthToken). Access tokens cannot be refreshed.
+func OAuth1Login(ctx context.Context, baseURL, consumerKey string, privateKey *rsa.PrivateKey, redirectURL string,
+	w io.Writer, logger logr.Logger) (*OAuthToken, error) {
+	signer := &oauth1Signer{consumerKey: consumerKey, privateKey: privateKey}
+	base := strings.TrimSuffix(baseURL, "/") + "/"
+
+	requestToken, err := signer.requestToken(ctx, base+oauth1RequestTokenPath,
+		map[string]string{"oauth_callback": redirectURL})
+	if err != nil {
+		logger.Info(fmt.Sprintf("Failed to get OAuth request token. Error: %v", err))
+		return nil, err
+	}
+	token := requestToken.Get("oauth_token")
+
+	authURL := base + oauth1AuthorizePath + "?" + url.Values{"oauth_token": {token}}.Encode()
+	query, err := waitForCallback(ctx, authURL, redirectURL, w, func(query url.Values) error {
+		switch {
+		case query.Get("oauth_token") != token:
+			return fmt.Errorf("invalid OAuth token in callback")
+		case query.Get("oauth_verifier") == "denied":
+			return fmt.Errorf("authorization denied")
+		case query.Get("oauth_verifier") == "":
+			return fmt.Errorf("no OAuth verifier in callback")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := signer.requestToken(ctx, base+oauth1AccessTokenPath,
+		map[string]string{"oauth_token": token, "oauth_verifier": query.Get("oauth_verifier")})
+	if err != nil {
+		logger.Info(fmt.Sprintf("Failed to get OAuth access token. Error: %v", err))
+		return nil, err
+	}
+
+	result := &OAuthToken{AccessToken: accessToken.Get("oauth_token"), APIURL: baseURL}
+	if expiresIn, err := strconv.ParseInt(accessToken.Get("oauth_expires_in"), 10, 64); err == nil && expiresIn > 0 {
+		result.Expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	if err := saveOAuthToken(baseURL, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// oauth1Transport signs requests with the cached OAuth 1.0a access token
+type oauth1Transport struct {
+	baseURL   string
+	signer    *oauth1Signer
+	token     *OAuthToken
+	transport http.RoundTripper
+}
+
+func (t *oauth1Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.token.expired() {
+		return nil, fmt.Errorf("%w: OAuth token for %s expired: run 'jira-utils auth login'", ErrNotLoggedIn, t.baseURL)
+	}
+
+	req2 := req.Clone(req.Context()) // per RoundTripper contract
+	authorization, err := t.signer.authorization(req2, map[string]string{"oauth_token": t.token.AccessToken})
+	if err != nil {
+		return nil, err
+	}
+	req2.Header.Set("Authorization", authorization)
+	return t.transport.RoundTrip(req2)
+}
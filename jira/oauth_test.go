@@ -0,0 +1,569 @@
+package jira
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// useTempConfigDir makes a temporary directory the config directory, where OAuth tokens are cached
+func useTempConfigDir(t *testing.T) string {
+	t.Helper()
+	clearSettings(t)
+	dir := t.TempDir()
+	t.Setenv(configPath, filepath.Join(dir, "config.yaml"))
+	if err := UseProfile(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// callbackURL returns a redirect URL on a free local port
+func callbackURL(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return fmt.Sprintf("http://%s/callback", listener.Addr())
+}
+
+// browser is the user authorizing access: it opens the first URL written to it,
+// following redirects up to the OAuth callback
+type browser struct {
+	mu   sync.Mutex
+	once sync.Once
+	buf  bytes.Buffer
+}
+
+func (b *browser) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Write(p)
+	scanner := bufio.NewScanner(bytes.NewReader(b.buf.Bytes()))
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "http") {
+			b.once.Do(func() {
+				go func() {
+					if resp, err := http.Get(line); err == nil {
+						resp.Body.Close()
+					}
+				}()
+			})
+		}
+	}
+	return len(p), nil
+}
+
+func TestOAuthTokenCache(t *testing.T) {
+	dir := useTempConfigDir(t)
+	baseURL := "https://jira.example.com"
+
+	if _, err := LoadOAuthToken(baseURL); !errors.Is(err, ErrNotLoggedIn) {
+		t.Fatalf("got error %v, want ErrNotLoggedIn", err)
+	}
+
+	token := &OAuthToken{AccessToken: "access", RefreshToken: "refresh",
+		Expiry: time.Now().Add(time.Hour).Truncate(time.Second), APIURL: baseURL}
+	if err := saveOAuthToken(baseURL, token); err != nil {
+		t.Fatal(err)
+	}
+	path, err := OAuthTokenPath(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != filepath.Join(dir, "oauth") {
+		t.Errorf("token cached in %s, want the oauth directory next to the config file", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("token file has permissions %#o, want 0600", perm)
+	}
+
+	loaded, err := LoadOAuthToken(baseURL + "/")
+	if err != nil {
+		t.Fatalf("LoadOAuthToken: %v", err)
+	}
+	if loaded.AccessToken != token.AccessToken || loaded.RefreshToken != token.RefreshToken ||
+		!loaded.Expiry.Equal(token.Expiry) || loaded.APIURL != token.APIURL {
+		t.Errorf("got token %+v, want %+v", loaded, token)
+	}
+
+	// each profile logs in separately
+	if err := UseProfile(&Config{Profiles: map[string]Profile{"prod": {BaseURL: baseURL}}}, "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOAuthToken(baseURL); !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("got error %v for another profile, want ErrNotLoggedIn", err)
+	}
+	if err := UseProfile(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOAuthToken(baseURL); err == nil || !strings.Contains(err.Error(), "permissions 0644") {
+		t.Errorf("got error %v, want token file permissions rejected", err)
+	}
+
+	if err := DeleteOAuthToken(baseURL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOAuthToken(baseURL); !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("got error %v after DeleteOAuthToken, want ErrNotLoggedIn", err)
+	}
+	if err := DeleteOAuthToken(baseURL); err != nil {
+		t.Errorf("deleting a missing token: %v", err)
+	}
+}
+
+// tokenEndpoint is a Jira Data Center OAuth 2.0 token endpoint
+type tokenEndpoint struct {
+	mu       sync.Mutex
+	requests []url.Values
+	// respond returns the status and JSON response to the token request form
+	respond func(form url.Values) (int, interface{})
+}
+
+func (e *tokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e.mu.Lock()
+	e.requests = append(e.requests, r.PostForm)
+	e.mu.Unlock()
+	status, response := e.respond(r.PostForm)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func TestRefreshOAuthToken(t *testing.T) {
+	tests := []struct {
+		name             string
+		refreshToken     string
+		status           int
+		response         map[string]interface{}
+		wantAccessToken  string
+		wantRefreshToken string
+		wantErr          string
+	}{
+		{name: "rotated refresh token", refreshToken: "refresh-1", status: http.StatusOK,
+			response:        map[string]interface{}{"access_token": "access-2", "refresh_token": "refresh-2", "expires_in": 3600},
+			wantAccessToken: "access-2", wantRefreshToken: "refresh-2"},
+		{name: "refresh token not rotated", refreshToken: "refresh-1", status: http.StatusOK,
+			response:        map[string]interface{}{"access_token": "access-2", "expires_in": 3600},
+			wantAccessToken: "access-2", wantRefreshToken: "refresh-1"},
+		{name: "rejected", refreshToken: "refresh-1", status: http.StatusBadRequest,
+			response: map[string]interface{}{"error": "invalid_grant", "error_description": "refresh token revoked"},
+			wantErr:  "invalid_grant refresh token revoked"},
+		{name: "no refresh token", wantErr: "cannot be refreshed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempConfigDir(t)
+			endpoint := &tokenEndpoint{respond: func(url.Values) (int, interface{}) { return tt.status, tt.response }}
+			server := httptest.NewServer(endpoint)
+			defer server.Close()
+
+			token := &OAuthToken{AccessToken: "access-1", RefreshToken: tt.refreshToken,
+				Expiry: time.Now().Add(-time.Minute), APIURL: server.URL}
+			refreshed, err := refreshOAuthToken(context.TODO(), server.URL, "client", "secret", token)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) ||
+					!strings.Contains(err.Error(), "jira-utils auth login") {
+					t.Fatalf("got error %v, want %q and how to log in again", err, tt.wantErr)
+				}
+				if _, err := LoadOAuthToken(server.URL); !errors.Is(err, ErrNotLoggedIn) {
+					t.Errorf("a token was cached after a failed refresh")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := url.Values{"grant_type": {"refresh_token"}, "client_id": {"client"}, "client_secret": {"secret"},
+				"refresh_token": {tt.refreshToken}}
+			if len(endpoint.requests) != 1 || endpoint.requests[0].Encode() != want.Encode() {
+				t.Errorf("got token requests %v, want %v", endpoint.requests, want)
+			}
+			if refreshed.AccessToken != tt.wantAccessToken || refreshed.RefreshToken != tt.wantRefreshToken ||
+				refreshed.APIURL != server.URL || refreshed.expired() {
+				t.Errorf("got token %+v", refreshed)
+			}
+			cached, err := LoadOAuthToken(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached.AccessToken != tt.wantAccessToken || cached.RefreshToken != tt.wantRefreshToken {
+				t.Errorf("got cached token %+v, want the refreshed one", cached)
+			}
+		})
+	}
+}
+
+func TestOAuthTransportRefreshesWithoutLock(t *testing.T) {
+	useTempConfigDir(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var startOnce sync.Once
+	endpoint := &tokenEndpoint{respond: func(url.Values) (int, interface{}) {
+		startOnce.Do(func() { close(started) })
+		<-release
+		return http.StatusOK, map[string]interface{}{"access_token": "access-2", "expires_in": 3600}
+	}}
+	mux := http.NewServeMux()
+	mux.Handle("/"+serverTokenPath, endpoint)
+	mux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	transport := &oauthTransport{baseURL: server.URL, clientID: "client", clientSecret: "secret",
+		token:     &OAuthToken{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: time.Now().Add(-time.Minute)},
+		transport: http.DefaultTransport}
+	client := &http.Client{Transport: transport}
+
+	const requests = 5
+	statuses := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			resp, err := client.Get(server.URL + "/rest/api/2/myself")
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("token was not refreshed")
+	}
+
+	// the lock is not held while refreshing...
+	locked := make(chan struct{})
+	go func() {
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock held while refreshing the token")
+	}
+
+	// ...so requests waiting for the refresh can be canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/rest/api/2/myself", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := client.Do(req)
+		canceled <- err
+	}()
+	cancel()
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("canceled request still waiting for the refresh")
+	}
+
+	close(release)
+	for i := 0; i < requests; i++ {
+		if status := <-statuses; status != http.StatusOK {
+			t.Errorf("request %d: got status %d, want 200", i, status)
+		}
+	}
+	if len(endpoint.requests) != 1 {
+		t.Errorf("token refreshed %d times, want once", len(endpoint.requests))
+	}
+	if cached, err := LoadOAuthToken(server.URL); err != nil || cached.AccessToken != "access-2" ||
+		cached.RefreshToken != "refresh-1" {
+		t.Errorf("got cached token %+v (error %v), want the refreshed one", cached, err)
+	}
+}
+
+func TestOAuthLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		denied  bool
+		wantErr string
+	}{
+		{name: "authorized"},
+		{name: "denied", denied: true, wantErr: "authorization denied: access_denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempConfigDir(t)
+
+			var challenge string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/"+serverAuthPath, func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				challenge = query.Get("code_challenge")
+				callback, _ := url.Parse(query.Get("redirect_uri"))
+				params := url.Values{"state": {query.Get("state")}, "code": {"code-1"}}
+				if tt.denied {
+					params = url.Values{"state": {query.Get("state")}, "error": {"access_denied"}}
+				}
+				callback.RawQuery = params.Encode()
+				http.Redirect(w, r, callback.String(), http.StatusFound)
+			})
+			mux.Handle("/"+serverTokenPath, &tokenEndpoint{respond: func(form url.Values) (int, interface{}) {
+				sum := sha256.Sum256([]byte(form.Get("code_verifier")))
+				if form.Get("code") != "code-1" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+					return http.StatusBadRequest, map[string]string{"error": "invalid_grant"}
+				}
+				return http.StatusOK, map[string]interface{}{"access_token": "access-1", "refresh_token": "refresh-1",
+					"expires_in": 3600}
+			}})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			token, err := OAuthLogin(context.TODO(), server.URL, "client", "secret", callbackURL(t), &browser{},
+				logr.Discard())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.APIURL != server.URL {
+				t.Errorf("got token %+v", token)
+			}
+			if cached, err := LoadOAuthToken(server.URL); err != nil || cached.AccessToken != "access-1" {
+				t.Errorf("got cached token %+v (error %v), want the new one", cached, err)
+			}
+		})
+	}
+}
+
+func TestOAuth1BaseString(t *testing.T) {
+	// example of RFC 5849 section 3.4.1.1, with the form body parameters in the query
+	req, err := http.NewRequest(http.MethodPost,
+		"HTTP://Example.com:80/request?b5=%3D%253D&a3=a&c%40=&a2=r%20b&c2&a3=2+q", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := oauth1BaseString(req, map[string]string{
+		"oauth_consumer_key":     "9djdj82h48djs9d2",
+		"oauth_token":            "kkk9d7dh3k39sjv7",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        "137131201",
+		"oauth_nonce":            "7d8f3e4a",
+	})
+	want := "POST&http%3A%2F%2Fexample.com%2Frequest&a2%3Dr%2520b%26a3%3D2%2520q%26a3%3Da%26b5%3D%253D%25253D%26c%2540%3D%26c2%3D" +
+		"%26oauth_consumer_key%3D9djdj82h48djs9d2%26oauth_nonce%3D7d8f3e4a%26oauth_signature_method%3DHMAC-SHA1" +
+		"%26oauth_timestamp%3D137131201%26oauth_token%3Dkkk9d7dh3k39sjv7"
+	if got != want {
+		t.Errorf("got base string\n%s\nwant\n%s", got, want)
+	}
+}
+
+// verifyOAuth1 returns the protocol parameters of r if it is signed by consumer "jira-utils" with key
+func verifyOAuth1(r *http.Request, key *rsa.PublicKey) (map[string]string, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "OAuth ") {
+		return nil, fmt.Errorf("no OAuth authorization")
+	}
+	params := map[string]string{}
+	for _, pair := range strings.Split(strings.TrimPrefix(header, "OAuth "), ", ") {
+		nameValue := strings.SplitN(pair, "=", 2)
+		value, err := url.PathUnescape(strings.Trim(nameValue[1], `"`))
+		if err != nil {
+			return nil, err
+		}
+		params[nameValue[0]] = value
+	}
+	signature, err := base64.StdEncoding.DecodeString(params["oauth_signature"])
+	if err != nil {
+		return nil, err
+	}
+	delete(params, "oauth_signature")
+	if params["oauth_consumer_key"] != "jira-utils" || params["oauth_signature_method"] != "RSA-SHA1" {
+		return nil, fmt.Errorf("unexpected parameters %v", params)
+	}
+
+	// the request as sent by the client
+	sent := r.Clone(r.Context())
+	sent.URL.Scheme, sent.URL.Host = "http", r.Host
+	sum := sha1.Sum([]byte(oauth1BaseString(sent, params)))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA1, sum[:], signature); err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	return params, nil
+}
+
+func TestOAuth1Login(t *testing.T) {
+	useTempConfigDir(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var callback string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+oauth1RequestTokenPath, func(w http.ResponseWriter, r *http.Request) {
+		params, err := verifyOAuth1(r, &key.PublicKey)
+		if err != nil {
+			http.Error(w, "oauth_problem=signature_invalid", http.StatusUnauthorized)
+			return
+		}
+		callback = params["oauth_callback"]
+		fmt.Fprint(w, "oauth_token=request-1&oauth_token_secret=secret&oauth_callback_confirmed=true")
+	})
+	mux.HandleFunc("/"+oauth1AuthorizePath, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, callback+"?oauth_token="+r.URL.Query().Get("oauth_token")+"&oauth_verifier=verifier-1",
+			http.StatusFound)
+	})
+	mux.HandleFunc("/"+oauth1AccessTokenPath, func(w http.ResponseWriter, r *http.Request) {
+		params, err := verifyOAuth1(r, &key.PublicKey)
+		if err != nil || params["oauth_token"] != "request-1" || params["oauth_verifier"] != "verifier-1" {
+			http.Error(w, "oauth_problem=token_rejected", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "oauth_token=access-1&oauth_token_secret=secret&oauth_expires_in=157680000")
+	})
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		if params, err := verifyOAuth1(r, &key.PublicKey); err != nil || params["oauth_token"] != "access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OAuth1Login(context.TODO(), server.URL, "jira-utils", otherKey, callbackURL(t), &browser{},
+		logr.Discard()); err == nil || !strings.Contains(err.Error(), "signature_invalid") {
+		t.Errorf("got error %v with another private key, want signature_invalid", err)
+	}
+
+	token, err := OAuth1Login(context.TODO(), server.URL, "jira-utils", key, callbackURL(t), &browser{}, logr.Discard())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "" || token.APIURL != server.URL ||
+		token.Expiry.Before(time.Now().AddDate(4, 0, 0)) {
+		t.Errorf("got token %+v", token)
+	}
+	if cached, err := LoadOAuthToken(server.URL); err != nil || cached.AccessToken != "access-1" {
+		t.Errorf("got cached token %+v (error %v), want the new one", cached, err)
+	}
+
+	// requests, query included, are signed with the access token
+	credentials := &Credentials{Type: AuthOAuth1, OAuthToken: token, oauthClientID: "jira-utils", oauthPrivateKey: key}
+	client := credentials.httpClient(server.URL, http.DefaultTransport)
+	resp, err := client.Get(server.URL + "/rest/api/2/search?jql=" + url.QueryEscape("project = CLOUDSTACK") + "&startAt=0")
+	if err != nil {
+		t.Fatalf("signed request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	token.Expiry = time.Now().Add(-time.Hour)
+	if _, err := client.Get(server.URL + "/rest/api/2/search"); !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("got error %v with an expired token, want ErrNotLoggedIn", err)
+	}
+}
+
+func TestLoadOAuthPrivateKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKey := func(name, content string, perm os.FileMode) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), perm); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs1 := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	pkcs8 := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER}))
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "PKCS #1", path: writeKey("pkcs1.pem", pkcs1, 0o600)},
+		{name: "PKCS #8", path: writeKey("pkcs8.pem", pkcs8, 0o600)},
+		{name: "readable by others", path: writeKey("public.pem", pkcs1, 0o644), wantErr: "permissions 0644"},
+		{name: "not PEM", path: writeKey("key.txt", "key", 0o600), wantErr: "no PEM encoded private key"},
+		{name: "missing", path: filepath.Join(dir, "missing.pem"), wantErr: "no such file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadOAuthPrivateKey(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(key) {
+				t.Error("got a different key")
+			}
+		})
+	}
+}
@@ -0,0 +1,473 @@
+package jira
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat is the format used to display results
+type OutputFormat string
+
+const (
+	// OutputTable displays results as an ASCII table (default)
+	OutputTable = OutputFormat("table")
+	// OutputJSON displays results as a JSON document
+	OutputJSON = OutputFormat("json")
+	// OutputYAML displays results as a YAML document
+	OutputYAML = OutputFormat("yaml")
+	// OutputCSV displays results as comma separated values, with a header line
+	OutputCSV = OutputFormat("csv")
+	// OutputTSV displays results as tab separated values, with a header line
+	OutputTSV = OutputFormat("tsv")
+	// OutputMarkdown displays results as a markdown table
+	OutputMarkdown = OutputFormat("markdown")
+)
+
+// ParseOutputFormat returns the OutputFormat with name format.
+// An empty format is the default one, table.
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(format)); f {
+	case "":
+		return OutputTable, nil
+	case OutputTable, OutputJSON, OutputYAML, OutputCSV, OutputTSV, OutputMarkdown:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (supported: table, json, yaml, csv, tsv, markdown)", format)
+	}
+}
+
+// IssueList is the document displayed for a list of issues with json and yaml output.
+type IssueList struct {
+	// Total is the number of issues matching the query
+	Total int `json:"total" yaml:"total"`
+	// Count is the number of issues in Issues
+	Count  int           `json:"count" yaml:"count"`
+	Issues []IssueRecord `json:"issues" yaml:"issues"`
+	// Groups is only set when grouping issues with --group-by
+	Groups []IssueGroup `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	// groupBy is the --group-by value, if any
+	groupBy string
+	// columns are the columns displayed by table, markdown and, when
+	// custom columns are requested, csv and tsv output
+	columns       []Column
+	customColumns bool
+}
+
+// IssueRecord is an issue as displayed by json, yaml, csv and tsv output.
+type IssueRecord struct {
+	Key      string    `json:"key" yaml:"key"`
+	Summary  string    `json:"summary" yaml:"summary"`
+	Status   string    `json:"status" yaml:"status"`
+	Assignee string    `json:"assignee" yaml:"assignee"`
+	Updated  time.Time `json:"updated" yaml:"updated"`
+	// DaysSinceUpdate is the number of days since the issue was last updated
+	DaysSinceUpdate int `json:"daysSinceUpdate" yaml:"daysSinceUpdate"`
+	// Warning is true when the issue has been in its status for longer than the --warn-after threshold
+	Warning bool `json:"warning" yaml:"warning"`
+	// WarningReason explains why the issue is flagged, e.g. "In Review for 3d, limit 2d"
+	WarningReason string `json:"warningReason,omitempty" yaml:"warningReason,omitempty"`
+	// Columns contains the value of each column requested with --columns, keyed by column name
+	Columns map[string]string `json:"columns,omitempty" yaml:"columns,omitempty"`
+
+	// Issue is the issue as returned by Jira. It is only available to templates.
+	Issue *jira.Issue `json:"-" yaml:"-"`
+
+	// cells contains the value of each column displayed
+	cells []string
+}
+
+// SprintList is the document displayed for a list of sprints with json and yaml output.
+type SprintList struct {
+	Count   int            `json:"count" yaml:"count"`
+	Sprints []SprintRecord `json:"sprints" yaml:"sprints"`
+}
+
+// SprintRecord is a sprint as displayed by json, yaml, csv and tsv output.
+type SprintRecord struct {
+	ID           int        `json:"id" yaml:"id"`
+	Name         string     `json:"name" yaml:"name"`
+	State        string     `json:"state" yaml:"state"`
+	StartDate    *time.Time `json:"startDate,omitempty" yaml:"startDate,omitempty"`
+	EndDate      *time.Time `json:"endDate,omitempty" yaml:"endDate,omitempty"`
+	CompleteDate *time.Time `json:"completeDate,omitempty" yaml:"completeDate,omitempty"`
+	Goal         string     `json:"goal,omitempty" yaml:"goal,omitempty"`
+	// DaysRemaining is the number of days until the end of an active sprint, rounded up
+	DaysRemaining *int `json:"daysRemaining,omitempty" yaml:"daysRemaining,omitempty"`
+	// OpenIssues and OpenPoints are the issues not done and their story points,
+	// DoneIssues and DonePoints the done ones
+	OpenIssues int     `json:"openIssues" yaml:"openIssues"`
+	DoneIssues int     `json:"doneIssues" yaml:"doneIssues"`
+	OpenPoints float64 `json:"openPoints" yaml:"openPoints"`
+	DonePoints float64 `json:"donePoints" yaml:"donePoints"`
+}
+
+// issueRecordHeaders are the csv/tsv headers for IssueRecord
+var issueRecordHeaders = []string{"key", "summary", "status", "assignee", "updated", "daysSinceUpdate", "warning",
+	"warningReason"}
+
+// sprintRecordHeaders are the csv/tsv headers for SprintRecord
+var sprintRecordHeaders = []string{"id", "name", "state", "startDate", "endDate", "completeDate", "goal",
+	"daysRemaining", "openIssues", "doneIssues", "openPoints", "donePoints"}
+
+// newIssueRecord returns the IssueRecord for issue, flagged if warningReason is set
+func newIssueRecord(issue *jira.Issue, warningReason string, columns []Column, customColumns bool) IssueRecord {
+	record := IssueRecord{Key: issue.Key, Warning: warningReason != "", WarningReason: warningReason, Issue: issue}
+	record.cells = make([]string, len(columns))
+	if customColumns {
+		record.Columns = make(map[string]string, len(columns))
+	}
+	for i := range columns {
+		record.cells[i] = columns[i].Value(issue)
+		if customColumns {
+			record.Columns[columns[i].Name] = record.cells[i]
+		}
+	}
+
+	if issue.Fields != nil {
+		record.Summary = issue.Fields.Summary
+		if issue.Fields.Status != nil {
+			record.Status = issue.Fields.Status.Name
+		} else {
+			record.Status = "N/A"
+		}
+		if issue.Fields.Assignee != nil {
+			record.Assignee = issue.Fields.Assignee.Name
+		}
+		record.Updated = time.Time(issue.Fields.Updated)
+		record.DaysSinceUpdate = int(time.Since(record.Updated).Hours() / 24)
+	}
+	return record
+}
+
+func (r *IssueRecord) values() []string {
+	return []string{r.Key, r.Summary, r.Status, r.Assignee, formatTime(&r.Updated),
+		fmt.Sprintf("%d", r.DaysSinceUpdate), fmt.Sprintf("%t", r.Warning), r.WarningReason}
+}
+
+// newSprintRecord returns the SprintRecord for sprint, with its issue counts, at time now
+func newSprintRecord(sprint *Sprint, counts *sprintIssueCounts, now time.Time) SprintRecord {
+	return SprintRecord{
+		ID:            sprint.ID,
+		Name:          sprint.Name,
+		State:         sprint.State,
+		StartDate:     sprint.StartDate,
+		EndDate:       sprint.EndDate,
+		CompleteDate:  sprint.CompleteDate,
+		Goal:          sprint.Goal,
+		DaysRemaining: daysRemaining(sprint, now),
+		OpenIssues:    counts.openIssues,
+		DoneIssues:    counts.doneIssues,
+		OpenPoints:    counts.openPoints,
+		DonePoints:    counts.donePoints,
+	}
+}
+
+func (r *SprintRecord) values() []string {
+	return []string{fmt.Sprintf("%d", r.ID), r.Name, r.State,
+		formatTime(r.StartDate), formatTime(r.EndDate), formatTime(r.CompleteDate), r.Goal,
+		r.daysRemaining(), fmt.Sprintf("%d", r.OpenIssues), fmt.Sprintf("%d", r.DoneIssues),
+		formatNumber(r.OpenPoints), formatNumber(r.DonePoints)}
+}
+
+// daysRemaining returns DaysRemaining, or an empty string if not set
+func (r *SprintRecord) daysRemaining() string {
+	if r.DaysRemaining == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *r.DaysRemaining)
+}
+
+// formatOptionalDate returns t formatted by formatDate, or an empty string if t is not set
+func formatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatDate(*t)
+}
+
+// writeIssues writes issues in the passed format
+func writeIssues(w io.Writer, format OutputFormat, list *IssueList) error {
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, list)
+	case OutputCSV, OutputTSV:
+		var headers []string
+		var rows [][]string
+		if list.customColumns {
+			for i := range list.columns {
+				headers = append(headers, list.columns[i].Name)
+			}
+			rows = issueCells(list, allIssues(list))
+		} else {
+			headers = issueRecordHeaders
+			for i := range list.Issues {
+				rows = append(rows, list.Issues[i].values())
+			}
+		}
+		if list.Groups == nil {
+			return writeSeparatedValues(w, format, headers, rows)
+		}
+		// with groups, each row starts with the group name (an issue can be in more groups)
+		groupedRows := make([][]string, 0)
+		for g := range list.Groups {
+			for _, i := range list.Groups[g].issues {
+				groupedRows = append(groupedRows, append([]string{list.Groups[g].Name}, rows[i]...))
+			}
+		}
+		return writeSeparatedValues(w, format, append([]string{"group"}, headers...), groupedRows)
+	}
+
+	headers := make([]string, len(list.columns))
+	for i := range list.columns {
+		headers[i] = list.columns[i].Header
+	}
+	caption := fmt.Sprintf("Showing %d of %d issues", list.Count, list.Total)
+
+	if list.Groups == nil {
+		return writeIssueTable(w, format, list, headers, allIssues(list), nil, caption)
+	}
+
+	for g := range list.Groups {
+		group := &list.Groups[g]
+		title := fmt.Sprintf("%s: %s (%d issues)", strings.ToUpper(list.groupBy), group.Name, group.Count)
+		if format == OutputMarkdown {
+			title = "### " + title
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", title); err != nil {
+			return err
+		}
+		if err := writeIssueTable(w, format, list, headers, group.issues, group.Subtotals, ""); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, caption)
+	return err
+}
+
+// writeIssueTable writes the issues with passed indexes as a table or markdown
+// table. If subtotals is set, a last row with the subtotals is added.
+func writeIssueTable(w io.Writer, format OutputFormat, list *IssueList, headers []string, issues []int,
+	subtotals map[string]float64, caption string) error {
+	rows := issueCells(list, issues)
+
+	var subtotalRow []string
+	if subtotals != nil {
+		subtotalRow = make([]string, len(list.columns))
+		for c := range list.columns {
+			if v, ok := subtotals[list.columns[c].Name]; ok {
+				subtotalRow[c] = formatNumber(v)
+			}
+		}
+		if subtotalRow[0] == "" {
+			subtotalRow[0] = "SUBTOTAL"
+		} else {
+			// first column has a subtotal: label goes in a column of its own
+			headers = append([]string{""}, headers...)
+			for r := range rows {
+				rows[r] = append([]string{""}, rows[r]...)
+			}
+			subtotalRow = append([]string{"SUBTOTAL"}, subtotalRow...)
+		}
+	}
+
+	if format == OutputMarkdown {
+		if subtotalRow != nil {
+			for c := range subtotalRow {
+				if subtotalRow[c] != "" {
+					subtotalRow[c] = "**" + subtotalRow[c] + "**"
+				}
+			}
+			rows = append(rows, subtotalRow)
+		}
+		return writeMarkdown(w, headers, rows, caption)
+	}
+
+	for r, i := range issues {
+		if list.Issues[i].Warning {
+			for c := range rows[r] {
+				rows[r][c] = color.New(color.FgRed).Sprint(rows[r][c])
+			}
+		}
+	}
+	table := newTable(w, headers)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+	if subtotalRow != nil {
+		// table footers collapse empty cells, so subtotals are a regular last row
+		table.Append(subtotalRow)
+	}
+	table.Render()
+	if caption != "" {
+		// not set as table caption, which is wrapped to the table width
+		if _, err := fmt.Fprintln(w, caption); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allIssues returns the indexes of all issues in list
+func allIssues(list *IssueList) []int {
+	issues := make([]int, len(list.Issues))
+	for i := range issues {
+		issues[i] = i
+	}
+	return issues
+}
+
+// issueCells returns a copy of the displayed column values of the issues with passed indexes
+func issueCells(list *IssueList, issues []int) [][]string {
+	rows := make([][]string, len(issues))
+	for r, i := range issues {
+		rows[r] = make([]string, len(list.Issues[i].cells))
+		copy(rows[r], list.Issues[i].cells)
+	}
+	return rows
+}
+
+// writeSprints writes sprints in the passed format
+func writeSprints(w io.Writer, format OutputFormat, list *SprintList) error {
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, list)
+	case OutputCSV, OutputTSV:
+		rows := make([][]string, len(list.Sprints))
+		for i := range list.Sprints {
+			rows[i] = list.Sprints[i].values()
+		}
+		return writeSeparatedValues(w, format, sprintRecordHeaders, rows)
+	}
+
+	headers := []string{"SPRINT", "STATE", "START", "END", "COMPLETE", "DAYS LEFT", "OPEN", "DONE", "POINTS DONE/ALL", "GOAL"}
+	rows := make([][]string, len(list.Sprints))
+	for i := range list.Sprints {
+		r := &list.Sprints[i]
+		rows[i] = []string{r.Name, r.State, formatOptionalDate(r.StartDate), formatOptionalDate(r.EndDate),
+			formatOptionalDate(r.CompleteDate), r.daysRemaining(), fmt.Sprintf("%d", r.OpenIssues),
+			fmt.Sprintf("%d", r.DoneIssues), fmt.Sprintf("%s/%s", formatNumber(r.DonePoints),
+				formatNumber(r.OpenPoints+r.DonePoints)), r.Goal}
+	}
+
+	if format == OutputMarkdown {
+		return writeMarkdown(w, headers, rows, "")
+	}
+
+	table := newTable(w, headers)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+	table.Render()
+	return nil
+}
+
+// writeBoards writes boards in the passed format
+func writeBoards(w io.Writer, format OutputFormat, list *BoardList) error {
+	rows := make([][]string, len(list.Boards))
+	for i := range list.Boards {
+		rows[i] = list.Boards[i].values()
+	}
+
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, list)
+	case OutputCSV, OutputTSV:
+		return writeSeparatedValues(w, format, boardRecordHeaders, rows)
+	}
+
+	headers := []string{"ID", "BOARD", "TYPE"}
+	if format == OutputMarkdown {
+		return writeMarkdown(w, headers, rows, "")
+	}
+
+	table := newTable(w, headers)
+	table.AppendBulk(rows)
+	table.Render()
+	return nil
+}
+
+func newTable(w io.Writer, headers []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(headers)
+	table.SetRowLine(true)
+	return table
+}
+
+// writeDocument writes doc as json or yaml
+func writeDocument(w io.Writer, format OutputFormat, doc interface{}) error {
+	if format == OutputYAML {
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// writeSeparatedValues writes headers and rows as csv or tsv
+func writeSeparatedValues(w io.Writer, format OutputFormat, headers []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if format == OutputTSV {
+		writer.Comma = '\t'
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// writeMarkdown writes headers and rows as a markdown table followed by caption, if any
+func writeMarkdown(w io.Writer, headers []string, rows [][]string, caption string) error {
+	escape := func(s string) string {
+		s = strings.ReplaceAll(s, "|", "\\|")
+		return strings.ReplaceAll(s, "\n", " ")
+	}
+
+	var sb strings.Builder
+	separators := make([]string, len(headers))
+	for i := range headers {
+		separators[i] = "---"
+	}
+	sb.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	sb.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+	for i := range rows {
+		cells := make([]string, len(rows[i]))
+		for j := range rows[i] {
+			cells[j] = escape(rows[i][j])
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	if caption != "" {
+		sb.WriteString("\n" + caption + "\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatTime returns t in RFC3339 format or an empty string if t is not set
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
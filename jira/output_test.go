@@ -0,0 +1,110 @@
+package jira
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/fatih/color"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// outputFormats are the formats covered by golden files, named testdata/<name>.<format>
+var outputFormats = []OutputFormat{OutputTable, OutputJSON, OutputYAML, OutputCSV, OutputTSV, OutputMarkdown}
+
+// checkGolden compares got with testdata/name, rewriting it with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
+
+// testIssueList returns a list of issues updated 3 and 10 days before now, and the time
+// each was updated: output contains them, so they are replaced before comparing with golden files
+func testIssueList(now time.Time) (*IssueList, []time.Time) {
+	updated := []time.Time{now.Add(-3*24*time.Hour - time.Hour).Truncate(time.Second),
+		now.Add(-10*24*time.Hour - time.Hour).Truncate(time.Second)}
+	issues := []jira.Issue{
+		{Key: "CLOUDSTACK-2263", Fields: &jira.IssueFields{Summary: "List registry requirements, with \"quotes\"",
+			Status: &jira.Status{Name: "In Progress"}, Assignee: &jira.User{Name: "rchincha"},
+			Updated: jira.Time(updated[0])}},
+		{Key: "CLOUDSTACK-2330", Fields: &jira.IssueFields{Summary: "Test jira | creating issues",
+			Updated: jira.Time(updated[1])}},
+	}
+
+	columns := defaultColumns()
+	list := &IssueList{Total: 5, Count: len(issues), Issues: make([]IssueRecord, len(issues)), columns: columns}
+	list.Issues[0] = newIssueRecord(&issues[0], "In Progress for 3d, limit 2d", columns, false)
+	list.Issues[1] = newIssueRecord(&issues[1], "", columns, false)
+	return list, updated
+}
+
+func TestWriteIssuesGolden(t *testing.T) {
+	color.NoColor = true
+	for _, format := range outputFormats {
+		t.Run(string(format), func(t *testing.T) {
+			list, updated := testIssueList(time.Now().UTC())
+			var out bytes.Buffer
+			if err := writeIssues(&out, format, list); err != nil {
+				t.Fatal(err)
+			}
+			got := out.String()
+			for i := range updated {
+				got = strings.ReplaceAll(got, updated[i].Format(time.RFC3339), "<updated>")
+			}
+			checkGolden(t, "issues."+string(format), []byte(got))
+		})
+	}
+}
+
+func TestWriteSprintsGolden(t *testing.T) {
+	date := func(day int) *time.Time {
+		d := time.Date(2022, 4, day, 9, 0, 0, 0, time.UTC)
+		return &d
+	}
+	sprints := []Sprint{
+		{Sprint: jira.Sprint{ID: 11, Name: "Sprint-41", State: SprintClosed, StartDate: date(4), EndDate: date(18),
+			CompleteDate: date(18)}, Goal: "Move to cluster-api v1.1"},
+		{Sprint: jira.Sprint{ID: 12, Name: "Sprint-42", State: SprintActive, StartDate: date(18),
+			EndDate: date(30)}, Goal: "Local registry for workload clusters"},
+		{Sprint: jira.Sprint{ID: 13, Name: "Sprint-43", State: SprintFuture}},
+	}
+	counts := []*sprintIssueCounts{{doneIssues: 1, donePoints: 5}, {openIssues: 2, doneIssues: 1, openPoints: 4,
+		donePoints: 2.5}, {}}
+	now := time.Date(2022, 4, 25, 12, 0, 0, 0, time.UTC)
+	list := &SprintList{Count: len(sprints), Sprints: make([]SprintRecord, len(sprints))}
+	for i := range sprints {
+		list.Sprints[i] = newSprintRecord(&sprints[i], counts[i], now)
+	}
+
+	for _, format := range outputFormats {
+		t.Run(string(format), func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeSprints(&out, format, list); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, "sprints."+string(format), out.Bytes())
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package jira_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+
+	jirautils "github.com/gianlucam76/jira_utils/jira"
+	"github.com/gianlucam76/jira_utils/jira/fake"
+)
+
+// sprintsFixture has two closed sprints: CLOUDSTACK-1 is done in Sprint-1, CLOUDSTACK-2 is carried
+// over from Sprint-1 to Sprint-2 (so searching sprint = 1 does not return it) and done in Sprint-2.
+// Sprint-0 was completed when it started.
+const sprintsFixture = `
+projects:
+  - {id: "10000", key: CLOUDSTACK, name: CloudStack}
+boards:
+  - {id: 1, name: CloudStack Scrum, type: scrum, project: CLOUDSTACK}
+sprints:
+  1:
+    - {id: 1, name: Sprint-1, state: closed, startDate: 2022-03-01T09:00:00Z, endDate: 2022-03-15T09:00:00Z,
+       completeDate: 2022-03-15T09:00:00Z}
+    - {id: 2, name: Sprint-2, state: closed, startDate: 2022-03-15T10:00:00Z, endDate: 2022-03-29T09:00:00Z,
+       completeDate: 2022-03-29T09:00:00Z}
+    - {id: 3, name: Sprint-0, state: closed, startDate: 2022-02-01T09:00:00Z, endDate: 2022-02-15T09:00:00Z,
+       completeDate: 2022-02-01T09:00:00Z}
+issues:
+  - id: "20001"
+    key: CLOUDSTACK-1
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: Resolved}
+      customfield_10002: 3
+      sprint: {id: 1, name: Sprint-1, state: closed}
+      created: "2022-02-20T10:00:00.000+0000"
+      updated: "2022-03-10T10:00:00.000+0000"
+    changelog:
+      histories:
+        - created: "2022-03-10T10:00:00.000+0000"
+          items: [{field: status, fromString: In Progress, toString: Resolved}]
+  - id: "20002"
+    key: CLOUDSTACK-2
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: Resolved}
+      customfield_10002: 5
+      sprint: {id: 2, name: Sprint-2, state: closed}
+      created: "2022-02-20T10:00:00.000+0000"
+      updated: "2022-03-20T10:00:00.000+0000"
+    changelog:
+      histories:
+        - created: "2022-03-15T09:30:00.000+0000"
+          items: [{field: Sprint, fromString: Sprint-1, toString: "Sprint-1, Sprint-2"}]
+        - created: "2022-03-20T10:00:00.000+0000"
+          items: [{field: status, fromString: In Progress, toString: Resolved}]
+fields:
+  - id: customfield_10002
+    name: Story Points
+    custom: true
+    schema: {type: number, custom: com.atlassian.jira.plugin.system.customfieldtypes:float, customId: 10002}
+`
+
+func newSprintsClient(t *testing.T) *fake.Client {
+	fixture, err := fake.ParseFixture([]byte(sprintsFixture))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return fake.NewClientFromFixture(fixture)
+}
+
+func TestGetVelocity(t *testing.T) {
+	type sprintWant struct {
+		name                 string
+		committed, completed float64
+	}
+	tests := []struct {
+		name        string
+		n           int
+		options     *jirautils.BurndownOptions
+		wantUnit    jirautils.BurndownUnit
+		wantSprints []sprintWant
+		wantAverage float64
+		wantStdDev  float64
+	}{
+		{
+			name:        "issues carried over are found with the project",
+			n:           2,
+			options:     &jirautils.BurndownOptions{Project: "CLOUDSTACK"},
+			wantUnit:    jirautils.UnitPoints,
+			wantSprints: []sprintWant{{"Sprint-1", 8, 3}, {"Sprint-2", 5, 5}},
+			wantAverage: 4,
+			wantStdDev:  1,
+		},
+		{
+			name:        "without the project only issues in the sprint now",
+			n:           2,
+			wantUnit:    jirautils.UnitPoints,
+			wantSprints: []sprintWant{{"Sprint-1", 3, 3}, {"Sprint-2", 5, 5}},
+			wantAverage: 4,
+			wantStdDev:  1,
+		},
+		{
+			name:        "issues",
+			n:           1,
+			options:     &jirautils.BurndownOptions{Project: "CLOUDSTACK", Unit: jirautils.UnitIssues},
+			wantUnit:    jirautils.UnitIssues,
+			wantSprints: []sprintWant{{"Sprint-2", 1, 1}},
+			wantAverage: 1,
+			wantStdDev:  0,
+		},
+		{
+			name:        "done statuses",
+			n:           2,
+			options:     &jirautils.BurndownOptions{Project: "CLOUDSTACK", DoneStatuses: []string{"Closed"}},
+			wantUnit:    jirautils.UnitPoints,
+			wantSprints: []sprintWant{{"Sprint-1", 8, 0}, {"Sprint-2", 5, 0}},
+			wantAverage: 0,
+			wantStdDev:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			velocity, err := jirautils.GetVelocity(context.TODO(), newSprintsClient(t), "1", tt.n, tt.options,
+				logr.Discard())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if velocity.Unit != tt.wantUnit {
+				t.Errorf("got unit %s, want %s", velocity.Unit, tt.wantUnit)
+			}
+			if len(velocity.Sprints) != len(tt.wantSprints) {
+				t.Fatalf("got %d sprints, want %d", len(velocity.Sprints), len(tt.wantSprints))
+			}
+			for i, want := range tt.wantSprints {
+				got := velocity.Sprints[i]
+				committed, completed := got.CommittedPoints, got.CompletedPoints
+				if tt.wantUnit == jirautils.UnitIssues {
+					committed, completed = float64(got.CommittedIssues), float64(got.CompletedIssues)
+				}
+				if got.Sprint != want.name || committed != want.committed || completed != want.completed {
+					t.Errorf("sprint %d: got %s %g/%g, want %s %g/%g", i, got.Sprint, committed, completed,
+						want.name, want.committed, want.completed)
+				}
+			}
+			if velocity.Average != tt.wantAverage || velocity.StdDev != tt.wantStdDev {
+				t.Errorf("got average %g and standard deviation %g, want %g and %g", velocity.Average,
+					velocity.StdDev, tt.wantAverage, tt.wantStdDev)
+			}
+		})
+	}
+}
+
+// searchCounter counts the issue searches sent to the fake client, by JQL
+type searchCounter struct {
+	*fake.Client
+	mu       sync.Mutex
+	searches map[string]int
+}
+
+func (c *searchCounter) SearchIssues(ctx context.Context, jql string, options *jira.SearchOptions) ([]jira.Issue,
+	int, error) {
+	c.mu.Lock()
+	c.searches[jql]++
+	c.mu.Unlock()
+	return c.Client.SearchIssues(ctx, jql, options)
+}
+
+func TestGetVelocitySearchesProjectOnce(t *testing.T) {
+	client := &searchCounter{Client: newSprintsClient(t), searches: map[string]int{}}
+	options := &jirautils.BurndownOptions{Project: "CLOUDSTACK"}
+	if _, err := jirautils.GetVelocity(context.TODO(), client, "1", 2, options, logr.Discard()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// from the day before Sprint-1, the oldest sprint, started
+	want := map[string]int{"sprint = 1": 1, "sprint = 2": 1,
+		`project = "CLOUDSTACK" AND updated >= "2022-02-28"`: 1}
+	if !reflect.DeepEqual(client.searches, want) {
+		t.Errorf("got searches %v, want %v", client.searches, want)
+	}
+}
+
+func TestGetVelocityNoClosedSprint(t *testing.T) {
+	client := newSprintsClient(t)
+	client.Sprints[1] = nil
+	_, err := jirautils.GetVelocity(context.TODO(), client, "1", 3, nil, logr.Discard())
+	if !errors.Is(err, jirautils.ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetBurndownZeroLengthSprint(t *testing.T) {
+	burndown, err := jirautils.GetBurndown(context.TODO(), newSprintsClient(t), "1", "Sprint-0", nil, logr.Discard())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range burndown.Days {
+		if ideal := burndown.Days[i].Ideal; math.IsNaN(ideal) || math.IsInf(ideal, 0) {
+			t.Errorf("day %d: ideal is %g", i, ideal)
+		}
+	}
+	if _, err := json.Marshal(burndown); err != nil {
+		t.Errorf("failed to marshal burndown: %v", err)
+	}
+}
+
+// scopeFixture has Sprint-2 of board 1 with: CLOUDSTACK-1 carried over from Sprint-1, CLOUDSTACK-2 moved to
+// Kube-5, a sprint of another board, CLOUDSTACK-3 moved from Parallel-1, a sprint of another board running at
+// the same time, and CLOUDSTACK-4 carried over from Sprint-1 then removed (so only the project search finds it)
+const scopeFixture = `
+projects:
+  - {id: "10000", key: CLOUDSTACK, name: CloudStack}
+boards:
+  - {id: 1, name: CloudStack Scrum, type: scrum, project: CLOUDSTACK}
+sprints:
+  1:
+    - {id: 1, name: Sprint-1, state: closed, startDate: 2022-03-01T09:00:00Z, endDate: 2022-03-15T09:00:00Z,
+       completeDate: 2022-03-15T09:00:00Z}
+    - {id: 2, name: Sprint-2, state: closed, startDate: 2022-03-15T10:00:00Z, endDate: 2022-03-29T09:00:00Z,
+       completeDate: 2022-03-29T09:00:00Z}
+issues:
+  - id: "20001"
+    key: CLOUDSTACK-1
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: Resolved}
+      sprint: {id: 2, name: Sprint-2, state: closed}
+      created: "2022-02-20T10:00:00.000+0000"
+      updated: "2022-03-20T10:00:00.000+0000"
+    changelog:
+      histories:
+        - created: "2022-03-15T09:30:00.000+0000"
+          items: [{field: Sprint, fromString: Sprint-1, toString: "Sprint-1, Sprint-2"}]
+  - id: "20002"
+    key: CLOUDSTACK-2
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: In Progress}
+      sprint: {id: 5, name: Kube-5, state: active}
+      created: "2022-02-20T10:00:00.000+0000"
+      updated: "2022-03-20T10:00:00.000+0000"
+    changelog:
+      histories:
+        - created: "2022-03-14T10:00:00.000+0000"
+          items: [{field: Sprint, fromString: "", toString: Sprint-2}]
+        - created: "2022-03-20T10:00:00.000+0000"
+          items: [{field: Sprint, fromString: Sprint-2, toString: Kube-5}]
+  - id: "20003"
+    key: CLOUDSTACK-3
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: Resolved}
+      sprint: {id: 2, name: Sprint-2, state: closed}
+      created: "2022-02-20T10:00:00.000+0000"
+      updated: "2022-03-16T10:00:00.000+0000"
+    changelog:
+      histories:
+        - created: "2022-03-16T10:00:00.000+0000"
+          items: [{field: Sprint, fromString: Parallel-1, toString: Sprint-2}]
+  - id: "20004"
+    key: CLOUDSTACK-4
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: In Progress}
+      sprint: {id: 1, name: Sprint-1, state: closed}
+      created: "2022-02-20T10:00:00.000+0000"
+      updated: "2022-03-20T10:00:00.000+0000"
+    changelog:
+      histories:
+        - created: "2022-03-15T09:30:00.000+0000"
+          items: [{field: Sprint, fromString: Sprint-1, toString: "Sprint-1, Sprint-2"}]
+        - created: "2022-03-20T10:00:00.000+0000"
+          items: [{field: Sprint, fromString: "Sprint-1, Sprint-2", toString: Sprint-1}]
+`
+
+func TestGetSprintScope(t *testing.T) {
+	fixture, err := fake.ParseFixture([]byte(scopeFixture))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	scope, err := jirautils.GetSprintScope(context.TODO(), fake.NewClientFromFixture(fixture), "1", "Sprint-2",
+		&jirautils.BurndownOptions{Project: "CLOUDSTACK"}, logr.Discard())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scopeKeys := func(issues []jirautils.SprintScopeIssue) []string {
+		result := make([]string, len(issues))
+		for i := range issues {
+			result[i] = issues[i].Key
+		}
+		sort.Strings(result)
+		return result
+	}
+	tests := []struct {
+		list string
+		got  []jirautils.SprintScopeIssue
+		want []string
+	}{
+		{"carried over", scope.CarriedOver, []string{"CLOUDSTACK-1"}},
+		{"added", scope.Added, []string{"CLOUDSTACK-3"}},
+		{"removed", scope.Removed, []string{"CLOUDSTACK-2", "CLOUDSTACK-4"}},
+		{"unresolved", scope.Unresolved, []string{}},
+	}
+	for _, tt := range tests {
+		if got := scopeKeys(tt.got); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.list, got, tt.want)
+		}
+	}
+	if len(scope.CarriedOver) == 1 && !reflect.DeepEqual(scope.CarriedOver[0].PreviousSprints, []string{"Sprint-1"}) {
+		t.Errorf("got previous sprints %v, want [Sprint-1]", scope.CarriedOver[0].PreviousSprints)
+	}
+	if scope.Count != 2 {
+		t.Errorf("got %d issues in the sprint when it ended, want 2", scope.Count)
+	}
+}
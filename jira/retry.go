@@ -0,0 +1,243 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// defaultMaxRetries is the number of times a failed request is retried when maxRetries is not set
+	defaultMaxRetries = 3
+	// defaultRequestTimeout is the timeout of each request when requestTimeout is not set
+	defaultRequestTimeout = time.Minute
+	// minBackoff and maxBackoff bound the exponential backoff between retries
+	minBackoff = 500 * time.Millisecond
+	maxBackoff = 30 * time.Second
+	// maxRetryAfter is the longest Retry-After honored: requests Jira asks to retry later are not retried
+	maxRetryAfter = 2 * time.Minute
+)
+
+// RetryOptions controls how requests to Jira are retried
+type RetryOptions struct {
+	// MaxRetries is the number of times a failed request is retried (0 disables retries)
+	MaxRetries int
+	// Timeout is the timeout of each attempt (0 means no timeout besides the context deadline)
+	Timeout time.Duration
+	// Budget is the maximum number of requests, retries included, sent by the client (0 means no limit)
+	Budget int
+}
+
+// GetRetryOptions returns the RetryOptions set with settings maxRetries,
+// requestTimeout and requestBudget (see LookupSetting)
+func GetRetryOptions() (*RetryOptions, error) {
+	options := &RetryOptions{MaxRetries: defaultMaxRetries, Timeout: defaultRequestTimeout}
+
+	if value, source := LookupSetting(MaxRetriesSetting); value != "" {
+		maxRetries, err := strconv.Atoi(value)
+		if err != nil || maxRetries < 0 {
+			return nil, fmt.Errorf("%s %q (from %s) must be a number >= 0", MaxRetriesSetting, value, source)
+		}
+		options.MaxRetries = maxRetries
+	}
+
+	if value, source := LookupSetting(RequestTimeoutSetting); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("%s %q (from %s) must be a duration, e.g. 30s or 2m", RequestTimeoutSetting, value, source)
+		}
+		options.Timeout = timeout
+	}
+
+	if value, source := LookupSetting(RequestBudgetSetting); value != "" {
+		budget, err := strconv.Atoi(value)
+		if err != nil || budget < 0 {
+			return nil, fmt.Errorf("%s %q (from %s) must be a number >= 0", RequestBudgetSetting, value, source)
+		}
+		options.Budget = budget
+	}
+
+	return options, nil
+}
+
+// retryTransport retries requests failing with a network error or rejected
+// because of rate limiting (429) or an unavailable server (502, 503, 504),
+// with exponential backoff and jitter, honoring Retry-After up to maxRetryAfter.
+// Requests which are not idempotent (e.g. POST) are retried on 429 only,
+// since Jira did not process them.
+type retryTransport struct {
+	options   RetryOptions
+	transport http.RoundTripper
+	logger    logr.Logger
+
+	mu     sync.Mutex
+	sent   int
+	jitter *rand.Rand
+}
+
+func newRetryTransport(transport http.RoundTripper, options *RetryOptions, logger logr.Logger) *retryTransport {
+	return &retryTransport{options: *options, transport: transport, logger: logger,
+		jitter: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.spend(); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.send(req, attempt)
+		retry, reason := t.shouldRetry(req, resp, err)
+		if !retry || attempt >= t.options.MaxRetries {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+			if ok && retryAfter > wait {
+				wait = retryAfter
+			}
+			// body is drained so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if ok && retryAfter > maxRetryAfter {
+				return nil, fmt.Errorf("%s %s failed (%s): not retried, Jira asks to wait %s (more than %s)",
+					req.Method, req.URL.Redacted(), reason, retryAfter.Round(time.Second), maxRetryAfter)
+			}
+		}
+
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+			return nil, fmt.Errorf("%s %s failed (%s): not retried, deadline exceeded before next attempt",
+				req.Method, req.URL.Redacted(), reason)
+		}
+
+		t.logger.Info(fmt.Sprintf("Retrying %s %s in %s (retry %d/%d): %s",
+			req.Method, req.URL.Redacted(), wait.Round(time.Millisecond), attempt+1, t.options.MaxRetries, reason))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// spend counts a request against the budget and returns an error if it is exhausted
+func (t *retryTransport) spend() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.options.Budget > 0 && t.sent >= t.options.Budget {
+		return fmt.Errorf("%w: %d requests already sent (setting %s)", ErrBudgetExceeded, t.sent, RequestBudgetSetting)
+	}
+	t.sent++
+	return nil
+}
+
+// send sends one attempt of req, with its own timeout
+func (t *retryTransport) send(req *http.Request, attempt int) (*http.Response, error) {
+	ctx := req.Context()
+	cancel := context.CancelFunc(func() {})
+	if t.options.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.options.Timeout)
+	}
+
+	attemptReq := req.WithContext(ctx)
+	if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := t.transport.RoundTrip(attemptReq)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// timeout also applies to reading the body: cancel when body is closed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// shouldRetry returns whether the attempt should be retried and why
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) (bool, string) {
+	if req.Context().Err() != nil {
+		return false, ""
+	}
+	// body can be sent again only if it can be recreated
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false, ""
+	}
+
+	if err != nil {
+		return idempotent(req.Method), err.Error()
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true, resp.Status
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(req.Method), resp.Status
+	}
+	return false, ""
+}
+
+// idempotent returns true if a request with method can be safely sent more than once
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before retry attempt+1: exponential,
+// bounded by maxBackoff, with jitter
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	ceiling := maxBackoff
+	if attempt < 16 {
+		if d := minBackoff << uint(attempt); d < ceiling {
+			ceiling = d
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return ceiling/2 + time.Duration(t.jitter.Int63n(int64(ceiling/2)+1))
+}
+
+// parseRetryAfter parses the value of header Retry-After: seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// cancelOnClose cancels the context of a request when its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,185 @@
+package jira
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// roundTripFunc is an http.RoundTripper answering with a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// replies returns a transport answering the n-th request with responses[n] (the last one afterwards),
+// and the number of requests received
+func replies(responses ...*http.Response) (http.RoundTripper, *int) {
+	sent := 0
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp := responses[len(responses)-1]
+		if sent < len(responses) {
+			resp = responses[sent]
+		}
+		sent++
+		clone := *resp
+		clone.Header = resp.Header.Clone()
+		clone.Body = io.NopCloser(strings.NewReader(""))
+		clone.Request = req
+		return &clone, nil
+	}), &sent
+}
+
+func response(status int, headers ...string) *http.Response {
+	resp := &http.Response{StatusCode: status, Status: http.StatusText(status), Header: http.Header{}}
+	for i := 0; i+1 < len(headers); i += 2 {
+		resp.Header.Set(headers[i], headers[i+1])
+	}
+	return resp
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %t; want %s, %t", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got, ok := parseRetryAfter(date); !ok || got < 59*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter(%q) = %s, %t; want about 1h", date, got, ok)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		responses  []*http.Response
+		options    RetryOptions
+		wantStatus int
+		wantErr    string
+		wantSent   int
+		minElapsed time.Duration
+	}{
+		{
+			name:       "success is not retried",
+			responses:  []*http.Response{response(http.StatusOK)},
+			options:    RetryOptions{MaxRetries: 3},
+			wantStatus: http.StatusOK,
+			wantSent:   1,
+		},
+		{
+			name:       "429 honors Retry-After",
+			responses:  []*http.Response{response(http.StatusTooManyRequests, "Retry-After", "1"), response(http.StatusOK)},
+			options:    RetryOptions{MaxRetries: 3},
+			wantStatus: http.StatusOK,
+			wantSent:   2,
+			minElapsed: time.Second,
+		},
+		{
+			name:      "Retry-After longer than maxRetryAfter is not waited for",
+			responses: []*http.Response{response(http.StatusTooManyRequests, "Retry-After", "3600"), response(http.StatusOK)},
+			options:   RetryOptions{MaxRetries: 3},
+			wantErr:   "not retried, Jira asks to wait 1h0m0s",
+			wantSent:  1,
+		},
+		{
+			name:       "retries stop at MaxRetries",
+			responses:  []*http.Response{response(http.StatusServiceUnavailable, "Retry-After", "0")},
+			options:    RetryOptions{MaxRetries: 2},
+			wantStatus: http.StatusServiceUnavailable,
+			wantSent:   3,
+		},
+		{
+			name:       "POST is not retried on 503",
+			method:     http.MethodPost,
+			responses:  []*http.Response{response(http.StatusServiceUnavailable), response(http.StatusOK)},
+			options:    RetryOptions{MaxRetries: 3},
+			wantStatus: http.StatusServiceUnavailable,
+			wantSent:   1,
+		},
+		{
+			name:      "budget",
+			responses: []*http.Response{response(http.StatusServiceUnavailable, "Retry-After", "0")},
+			options:   RetryOptions{MaxRetries: 5, Budget: 2},
+			wantErr:   ErrBudgetExceeded.Error(),
+			wantSent:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, sent := replies(tt.responses...)
+			retry := newRetryTransport(transport, &tt.options, logr.Discard())
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, err := http.NewRequest(method, "https://jira.example.com/rest/api/2/search", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			resp, err := retry.RoundTrip(req)
+			elapsed := time.Since(start)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else {
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+			if *sent != tt.wantSent {
+				t.Errorf("sent %d requests, want %d", *sent, tt.wantSent)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("returned after %s, want at least %s", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestRetryTransportBudgetError(t *testing.T) {
+	transport, _ := replies(response(http.StatusOK))
+	retry := newRetryTransport(transport, &RetryOptions{Budget: 1}, logr.Discard())
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://jira.example.com/rest/api/2/field", nil)
+		resp, err := retry.RoundTrip(req)
+		if i == 0 {
+			if err != nil {
+				t.Fatalf("first request: %v", err)
+			}
+			resp.Body.Close()
+			continue
+		}
+		if !errors.Is(err, ErrBudgetExceeded) {
+			t.Errorf("second request: got error %v, want ErrBudgetExceeded", err)
+		}
+	}
+}
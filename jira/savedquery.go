@@ -0,0 +1,172 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+)
+
+// SavedQuery is a named JQL query defined in the config file.
+// JQL is a Go text/template and can contain these placeholders:
+//   - {{.Me}}: the current user
+//   - {{.Project}}: the project name
+//   - {{.ActiveSprint}}: the name of the active sprint of the board
+type SavedQuery struct {
+	JQL         string `yaml:"jql"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// UnmarshalYAML allows a saved query to be defined either as a mapping
+// (jql, description) or just as its JQL
+func (q *SavedQuery) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		q.JQL = value.Value
+		return nil
+	}
+	type plain SavedQuery
+	return value.Decode((*plain)(q))
+}
+
+// builtinQueries are the saved queries always available.
+// Config file can override them.
+var builtinQueries = map[string]SavedQuery{
+	"e2e": {
+		JQL:         `Status NOT IN (Resolved,Closed) and reporter = atom-ci.gen and project = "{{.Project}}"`,
+		Description: "Open issues filed for e2e automatic tagging sanities",
+	},
+}
+
+// GetSavedQuery returns the saved query with passed name, looking first
+// in config then in the builtin queries. Returns an error if not found.
+func GetSavedQuery(config *Config, name string) (*SavedQuery, error) {
+	if config != nil {
+		if q, ok := config.Queries[name]; ok {
+			return &q, nil
+		}
+	}
+	if q, ok := builtinQueries[name]; ok {
+		return &q, nil
+	}
+	return nil, fmt.Errorf("saved query %q not found (available: %s)", name,
+		strings.Join(SavedQueryNames(config), ", "))
+}
+
+// SavedQueryNames returns the sorted names of all saved queries, builtin included
+func SavedQueryNames(config *Config) []string {
+	names := make([]string, 0)
+	for name := range builtinQueries {
+		names = append(names, name)
+	}
+	if config != nil {
+		for name := range config.Queries {
+			if _, ok := builtinQueries[name]; !ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// QueryVariables are the values of the placeholders available in saved queries.
+// Project and active sprint are fetched from Jira only if used by the query.
+type QueryVariables struct {
+	ctx         context.Context
+	jiraClient  JiraAPI
+	username    string
+	projectName string
+	boardName   string
+	logger      logr.Logger
+
+	project *jira.Project
+}
+
+// NewQueryVariables returns the QueryVariables for user username, project
+// projectName and board boardName. Empty values default to the ones in
+// settings (see LookupSetting).
+func NewQueryVariables(ctx context.Context, jiraClient JiraAPI, username, projectName, boardName string,
+	logger logr.Logger) *QueryVariables {
+	return &QueryVariables{ctx: ctx, jiraClient: jiraClient, username: username,
+		projectName: projectName, boardName: boardName, logger: logger}
+}
+
+// Me returns the current user
+func (v *QueryVariables) Me() (string, error) {
+	if v.username != "" {
+		return v.username, nil
+	}
+	return GetUsername(v.logger)
+}
+
+// Project returns the project name
+func (v *QueryVariables) Project() (string, error) {
+	project, err := v.getProject()
+	if err != nil {
+		return "", err
+	}
+	return project.Name, nil
+}
+
+// ActiveSprint returns the name of the active sprint of the board
+func (v *QueryVariables) ActiveSprint() (string, error) {
+	project, err := v.getProject()
+	if err != nil {
+		return "", err
+	}
+	board, err := GetJiraBoard(v.ctx, v.jiraClient, project.Key, v.boardName, v.logger)
+	if err != nil {
+		return "", err
+	}
+	sprint, err := GetJiraActiveSprint(v.ctx, v.jiraClient, fmt.Sprintf("%d", board.ID), v.logger)
+	if err != nil {
+		return "", err
+	}
+	return sprint.Name, nil
+}
+
+func (v *QueryVariables) getProject() (*jira.Project, error) {
+	if v.project != nil {
+		return v.project, nil
+	}
+	project, err := GetJiraProject(v.ctx, v.jiraClient, v.projectName, v.logger)
+	if err != nil {
+		return nil, err
+	}
+	v.project = project
+	return project, nil
+}
+
+// ExpandQuery returns the JQL of query with all placeholders replaced by their value
+func ExpandQuery(name string, query *SavedQuery, variables *QueryVariables) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(query.JQL)
+	if err != nil {
+		return "", fmt.Errorf("invalid saved query %q: %w", name, err)
+	}
+	var jql bytes.Buffer
+	if err := tmpl.Execute(&jql, variables); err != nil {
+		return "", fmt.Errorf("failed to expand saved query %q: %w", name, err)
+	}
+	return strings.TrimSpace(jql.String()), nil
+}
+
+// DisplaySavedQueries writes a table with name, description and JQL of all saved queries
+func DisplaySavedQueries(config *Config, w io.Writer) {
+	table := newTable(w, []string{"NAME", "DESCRIPTION", "JQL"})
+	table.SetAutoWrapText(false)
+	for _, name := range SavedQueryNames(config) {
+		query, err := GetSavedQuery(config, name)
+		if err != nil {
+			continue
+		}
+		table.Append([]string{name, query.Description, query.JQL})
+	}
+	table.Render()
+}
@@ -0,0 +1,366 @@
+package jira
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SecretStoreType is where secrets (password, token, OAuth client secret) not set
+// with env variables or in the config profile are read from
+type SecretStoreType string
+
+const (
+	// SecretStoreHelper runs an external credential helper, speaking the git credential protocol
+	SecretStoreHelper = SecretStoreType("helper")
+	// SecretStoreFile reads secrets from a YAML file readable by the current user only
+	SecretStoreFile = SecretStoreType("file")
+	// SecretStoreVault reads secrets from a file encrypted with a passphrase
+	SecretStoreVault = SecretStoreType("vault")
+)
+
+const (
+	// defaultSecretsFile and defaultVaultFile are the files, in the config file
+	// directory, used by SecretStoreFile and SecretStoreVault when no path is set
+	defaultSecretsFile = "secrets.yaml"
+	defaultVaultFile   = "vault.json"
+
+	// helperTimeout is how long the credential helper can take to return a secret
+	helperTimeout = 30 * time.Second
+)
+
+// SecretStore is a source of secrets, set with setting secretStore as
+// helper:<command>, file[:<path>] or vault[:<path>]
+type SecretStore struct {
+	Type SecretStoreType
+	// Location is the helper command (helper) or the file path (file, vault)
+	Location string
+
+	// passphrase unlocks the vault, asked once per SecretStore
+	passphrase string
+}
+
+// ParseSecretStore returns the SecretStore described by value. Returns nil if value is empty.
+func ParseSecretStore(value string) (*SecretStore, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	kind, location := value, ""
+	if i := strings.Index(value, ":"); i >= 0 {
+		kind, location = value[:i], strings.TrimSpace(value[i+1:])
+	}
+
+	store := &SecretStore{Type: SecretStoreType(strings.ToLower(kind)), Location: location}
+	switch store.Type {
+	case SecretStoreHelper:
+		if location == "" {
+			return nil, fmt.Errorf("secret store %q: helper command is missing (e.g. helper:pass-jira)", value)
+		}
+	case SecretStoreFile, SecretStoreVault:
+		path, err := secretStorePath(store.Type, location)
+		if err != nil {
+			return nil, err
+		}
+		store.Location = path
+	default:
+		return nil, fmt.Errorf("unsupported secret store %q (supported: %s:<command>, %s[:<path>], %s[:<path>])",
+			value, SecretStoreHelper, SecretStoreFile, SecretStoreVault)
+	}
+	return store, nil
+}
+
+// secretStorePath returns the file used by storeType: path with ~ expanded,
+// the default file in the config file directory if path is empty
+func secretStorePath(storeType SecretStoreType, path string) (string, error) {
+	switch {
+	case path == "":
+		dir, err := configDir()
+		if err != nil {
+			return "", err
+		}
+		name := defaultSecretsFile
+		if storeType == SecretStoreVault {
+			name = defaultVaultFile
+		}
+		return filepath.Join(dir, name), nil
+	case path == "~" || strings.HasPrefix(path, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+	}
+	return path, nil
+}
+
+func (s *SecretStore) String() string {
+	return fmt.Sprintf("%s:%s", s.Type, s.Location)
+}
+
+// ActiveSecretStore returns the secret store set with setting secretStore, nil if not set
+func ActiveSecretStore() (*SecretStore, error) {
+	value, source := LookupSetting(SecretStoreSetting)
+	store, err := ParseSecretStore(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s (from %s): %w", SecretStoreSetting, source, err)
+	}
+	return store, nil
+}
+
+// IsSecretSetting returns true if setting name is a secret: password, token or OAuth client secret
+func IsSecretSetting(name string) bool {
+	return secretSettings[name]
+}
+
+// LookupSecret returns the value of secret setting name: the value set with
+// env variable or in the profile if any (password is base64 decoded),
+// the value in the secret store in use otherwise
+func LookupSecret(name string) (string, error) {
+	if value, source := LookupSetting(name); value != "" {
+		if name != PasswordSetting {
+			return value, nil
+		}
+		password, err := decodePassword(value)
+		if err != nil {
+			return "", fmt.Errorf("%s (from %s) is not base64 encoded: %w", PasswordSetting, source, err)
+		}
+		return password, nil
+	}
+
+	store, err := ActiveSecretStore()
+	if err != nil || store == nil {
+		return "", err
+	}
+	return store.Get(name)
+}
+
+// storedSecret returns whether secret name is in the secret store in use. Only file stores
+// are looked up (verified is true): vault and helper stores would ask for the passphrase or
+// run the credential helper, so their secrets are assumed found.
+func storedSecret(name string) (found, verified bool) {
+	store, err := ActiveSecretStore()
+	if err != nil {
+		// invalid secretStore setting is reported when secrets are read
+		return true, false
+	}
+	if store == nil {
+		return false, true
+	}
+	if store.Type != SecretStoreFile {
+		return true, false
+	}
+	_, err = store.Get(name)
+	return err == nil, true
+}
+
+// Get returns the value of secret name in the store.
+// The error matches ErrMissingConfig if the store has no secret name.
+func (s *SecretStore) Get(name string) (string, error) {
+	if s.Type == SecretStoreHelper {
+		return s.helperGet(name)
+	}
+
+	secrets, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[name]
+	if !ok || value == "" {
+		return "", fmt.Errorf("%s not found in secret store %s: %w", name, s, ErrMissingConfig)
+	}
+	return value, nil
+}
+
+// Set stores value as secret name (file and vault only)
+func (s *SecretStore) Set(name, value string) error {
+	secrets, err := s.readForUpdate()
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return s.write(secrets)
+}
+
+// Remove removes secret name from the store (file and vault only)
+func (s *SecretStore) Remove(name string) error {
+	secrets, err := s.readForUpdate()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return fmt.Errorf("%s not found in secret store %s", name, s)
+	}
+	delete(secrets, name)
+	return s.write(secrets)
+}
+
+// Names returns the names of the secrets in the store, sorted (file and vault only)
+func (s *SecretStore) Names() ([]string, error) {
+	if s.Type == SecretStoreHelper {
+		return nil, fmt.Errorf("secrets in %s cannot be listed: use the helper own commands", s)
+	}
+	secrets, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// read returns all secrets in the file or vault
+func (s *SecretStore) read() (map[string]string, error) {
+	if err := checkPrivateFile(s.Location); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("secret store %s does not exist: add secrets with 'jira-utils secret set'", s)
+		}
+		return nil, err
+	}
+	content, err := os.ReadFile(s.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Type == SecretStoreVault {
+		if s.passphrase == "" {
+			if s.passphrase, err = vaultPassphrase(s.Location, false); err != nil {
+				return nil, err
+			}
+		}
+		return openVault(s.Location, content, s.passphrase)
+	}
+
+	secrets := map[string]string{}
+	if err := yaml.Unmarshal(content, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file %s: %w", s.Location, err)
+	}
+	return secrets, nil
+}
+
+// readForUpdate returns all secrets in the file or vault, none if it does not exist yet
+func (s *SecretStore) readForUpdate() (map[string]string, error) {
+	if s.Type == SecretStoreHelper {
+		return nil, fmt.Errorf("secrets in %s cannot be changed: use the helper own commands", s)
+	}
+	if _, err := os.Stat(s.Location); os.IsNotExist(err) {
+		if s.Type == SecretStoreVault && s.passphrase == "" {
+			if s.passphrase, err = vaultPassphrase(s.Location, true); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]string{}, nil
+	}
+	return s.read()
+}
+
+// write replaces the content of the file or vault with secrets
+func (s *SecretStore) write(secrets map[string]string) error {
+	if s.Type == SecretStoreVault {
+		content, err := sealVault(secrets, s.passphrase)
+		if err != nil {
+			return err
+		}
+		return writePrivateFile(s.Location, content)
+	}
+
+	content, err := yaml.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.Location, content)
+}
+
+// helperGet asks the credential helper for secret name. As with git credential
+// helpers, "<command> get" is run with protocol, host, path and username of
+// the Jira base URL on stdin, and must print the secret as password=<value>.
+// Username is the OAuth client ID when asking for the OAuth client secret.
+func (s *SecretStore) helperGet(name string) (string, error) {
+	var input bytes.Buffer
+	baseURL, _ := LookupSetting(BaseURLSetting)
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		fmt.Fprintf(&input, "protocol=%s\nhost=%s\n", u.Scheme, u.Host)
+		if path := strings.Trim(u.Path, "/"); path != "" {
+			fmt.Fprintf(&input, "path=%s\n", path)
+		}
+	}
+	username, _ := LookupSetting(UsernameSetting)
+	if name == OAuthClientSecretSetting {
+		username, _ = LookupSetting(OAuthClientIDSetting)
+	}
+	if username != "" {
+		fmt.Fprintf(&input, "username=%s\n", username)
+	}
+	input.WriteString("\n")
+
+	// a hung helper must not hang every command
+	ctx, cancel := context.WithTimeout(context.Background(), helperTimeout)
+	defer cancel()
+	output, err := runHelper(ctx, s.Location, &input)
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("credential helper %q did not answer within %s", s.Location, helperTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("credential helper %q failed: %w", s.Location, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if value := strings.TrimPrefix(scanner.Text(), "password="); value != scanner.Text() && value != "" {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("credential helper %q returned no password for %s: %w", s.Location, name, ErrMissingConfig)
+}
+
+// runHelper runs "<command> get" with input on stdin and returns its stdout.
+// The command is killed when ctx is done.
+func runHelper(ctx context.Context, command string, input io.Reader) ([]byte, error) {
+	args := strings.Fields(command)
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], "get")...)
+	cmd.Stdin = input
+	cmd.Stderr = os.Stderr
+
+	// stdout is read here, not by exec: processes started by the helper and still
+	// running once it is killed would otherwise keep the pipe, and Wait, blocked
+	stdout, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.Close()
+	cmd.Stdout = w
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	output := make(chan []byte, 1)
+	go func() {
+		b, _ := io.ReadAll(stdout)
+		output <- b
+	}()
+	if err := cmd.Wait(); err != nil {
+		return nil, err
+	}
+	select {
+	case b := <-output:
+		return b, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
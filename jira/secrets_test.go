@@ -0,0 +1,154 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeHelper writes a credential helper shell script running script and
+// returns its path. The helper saves its stdin to file input in the same directory.
+func writeHelper(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "helper")
+	content := "#!/bin/sh\ncat > \"$(dirname \"$0\")/input\"\n" + script + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHelperGet(t *testing.T) {
+	tests := []struct {
+		name string
+		// args are appended to the helper command
+		args      string
+		script    string
+		secret    string
+		want      string
+		wantInput string
+		wantErr   string
+	}{
+		{name: "password", script: `[ "$1" = get ] && echo username=dev && echo password=s3cret`,
+			secret: PasswordSetting, want: "s3cret",
+			wantInput: "protocol=https\nhost=jira.example.com\npath=jira\nusername=dev\n\n"},
+		{name: "OAuth client secret", script: "echo password=client-s3cret", secret: OAuthClientSecretSetting,
+			want: "client-s3cret", wantInput: "protocol=https\nhost=jira.example.com\npath=jira\nusername=client\n\n"},
+		{name: "command with args", args: " --store jira", script: `echo "password=$*"`, secret: TokenSetting,
+			want: "--store jira get"},
+		{name: "no password", script: "echo username=dev", secret: PasswordSetting,
+			wantErr: "returned no password"},
+		{name: "empty password", script: "echo password=", secret: PasswordSetting,
+			wantErr: "returned no password"},
+		{name: "helper fails", script: "exit 3", secret: PasswordSetting, wantErr: "exit status 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearSettings(t)
+			t.Setenv(jiraBaseURL, "https://jira.example.com/jira/")
+			t.Setenv(username, "dev")
+			t.Setenv(jiraOAuthClientID, "client")
+			if err := UseProfile(nil, ""); err != nil {
+				t.Fatal(err)
+			}
+			helper := writeHelper(t, tt.script)
+
+			store, err := ParseSecretStore("helper:" + helper + tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := store.Get(tt.secret)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				if strings.Contains(tt.wantErr, "no password") && !errors.Is(err, ErrMissingConfig) {
+					t.Errorf("got error %v, want ErrMissingConfig", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got secret %q, want %q", got, tt.want)
+			}
+			if tt.wantInput != "" {
+				input, err := os.ReadFile(filepath.Join(filepath.Dir(helper), "input"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(input) != tt.wantInput {
+					t.Errorf("got helper input %q, want %q", input, tt.wantInput)
+				}
+			}
+		})
+	}
+}
+
+func TestRunHelperKilled(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{name: "hung helper", script: "exec sleep 10"},
+		// a process started by the helper keeps stdout open after the helper exits
+		{name: "helper leaving a child", script: "sleep 2 &\necho password=s3cret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := writeHelper(t, tt.script)
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			if _, err := runHelper(ctx, helper, strings.NewReader("\n")); err == nil {
+				t.Errorf("got no error, want the helper killed at the deadline")
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("runHelper returned after %s, want at the deadline", elapsed)
+			}
+		})
+	}
+}
+
+func TestFileStorePermissions(t *testing.T) {
+	clearSettings(t)
+	if err := UseProfile(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	if err := os.WriteFile(path, []byte("password: s3cret\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(jiraSecretStore, "file:"+path)
+
+	if _, err := LookupSecret(PasswordSetting); err == nil || !strings.Contains(err.Error(), "permissions 0644") {
+		t.Errorf("got error %v reading a file store accessible by others, want permissions error", err)
+	}
+	var missingErr *MissingSettingsError
+	if err := VerifySettings(PasswordSetting); !errors.As(err, &missingErr) ||
+		!reflect.DeepEqual(missingErr.Settings, []string{PasswordSetting}) {
+		t.Errorf("got error %v, want password missing", err)
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := LookupSecret(PasswordSetting); err != nil || value != "s3cret" {
+		t.Errorf("got %q, %v, want the stored password", value, err)
+	}
+	if err := VerifySettings(PasswordSetting); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
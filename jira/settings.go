@@ -0,0 +1,374 @@
+package jira
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// Settings needed to access Jira, as named in config file profiles
+const (
+	BaseURLSetting  = "baseURL"
+	ProjectSetting  = "project"
+	BoardSetting    = "board"
+	UsernameSetting = "username"
+	PasswordSetting = "password"
+	AuthSetting     = "auth"
+	TokenSetting    = "token"
+
+	OAuthClientIDSetting     = "oauthClientID"
+	OAuthClientSecretSetting = "oauthClientSecret"
+	OAuthPrivateKeySetting   = "oauthPrivateKey"
+
+	SecretStoreSetting = "secretStore"
+
+	MaxRetriesSetting     = "maxRetries"
+	RequestTimeoutSetting = "requestTimeout"
+	RequestBudgetSetting  = "requestBudget"
+
+	CacheTTLSetting = "cacheTTL"
+
+	WarnAfterSetting = "warnAfter"
+)
+
+// Sources a setting value can come from, from the highest to the lowest priority.
+// Flags (e.g. --project) are handled by each command and take precedence over all of them.
+const (
+	SourceEnv     = "env"
+	SourceProfile = "profile"
+	// SourceSecretStore is reported for secrets not set with env variables or in the
+	// profile when a secret store is in use: they are read from it only when needed
+	SourceSecretStore = "secretStore"
+	// SourceSecretStoreUnverified is reported instead when the secret store cannot be read
+	// without asking for the passphrase or running the credential helper (vault and helper)
+	SourceSecretStoreUnverified = "secretStore (unverified)"
+	SourceUnset                 = "unset"
+)
+
+// settingNames are all settings, in display order
+var settingNames = []string{BaseURLSetting, ProjectSetting, BoardSetting, AuthSetting, UsernameSetting,
+	PasswordSetting, TokenSetting, OAuthClientIDSetting, OAuthClientSecretSetting, OAuthPrivateKeySetting,
+	SecretStoreSetting,
+	MaxRetriesSetting, RequestTimeoutSetting, RequestBudgetSetting, CacheTTLSetting,
+	WarnAfterSetting}
+
+// secretSettings are the settings whose value is never displayed
+var secretSettings = map[string]bool{PasswordSetting: true, TokenSetting: true, OAuthClientSecretSetting: true}
+
+// settingEnvVariables are the env variables overriding each setting
+var settingEnvVariables = map[string]string{
+	BaseURLSetting:  jiraBaseURL,
+	ProjectSetting:  jiraProject,
+	BoardSetting:    jiraBoardName,
+	UsernameSetting: username,
+	PasswordSetting: password,
+	AuthSetting:     jiraAuth,
+	TokenSetting:    jiraToken,
+
+	OAuthClientIDSetting:     jiraOAuthClientID,
+	OAuthClientSecretSetting: jiraOAuthClientSecret,
+	OAuthPrivateKeySetting:   jiraOAuthPrivateKey,
+
+	SecretStoreSetting: jiraSecretStore,
+
+	MaxRetriesSetting:     jiraMaxRetries,
+	RequestTimeoutSetting: jiraRequestTimeout,
+	RequestBudgetSetting:  jiraRequestBudget,
+
+	CacheTTLSetting: jiraCacheTTL,
+
+	WarnAfterSetting: jiraWarnAfter,
+}
+
+// Profile is a named set of settings in the config file
+type Profile struct {
+	BaseURL  string `yaml:"baseURL,omitempty"`
+	Project  string `yaml:"project,omitempty"`
+	Board    string `yaml:"board,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	// Password is base64 encoded, as in env variable JIRA_PASSWORD
+	Password string `yaml:"password,omitempty"`
+	// Auth is the AuthType: basic (default), bearer, cloud, cookie or oauth
+	Auth string `yaml:"auth,omitempty"`
+	// Token is the personal access token (bearer), API token (cloud) or session cookie (cookie)
+	Token string `yaml:"token,omitempty"`
+	// OAuthClientID and OAuthClientSecret identify the OAuth application registered in Jira (oauth).
+	// With oauth1, OAuthClientID is the consumer key of the application link and OAuthPrivateKey
+	// the path of the PEM encoded private key whose public key is set in the application link.
+	OAuthClientID     string `yaml:"oauthClientID,omitempty"`
+	OAuthClientSecret string `yaml:"oauthClientSecret,omitempty"`
+	OAuthPrivateKey   string `yaml:"oauthPrivateKey,omitempty"`
+	// SecretStore is where secrets not set in the profile nor with env variables are read from:
+	// helper:<command>, file[:<path>] or vault[:<path>]
+	SecretStore string `yaml:"secretStore,omitempty"`
+	// MaxRetries, RequestTimeout and RequestBudget control how requests are retried (see RetryOptions):
+	// number of retries (3 by default), timeout of each request (e.g. 30s, 1m by default) and
+	// maximum number of requests sent by a command (no limit by default)
+	MaxRetries     string `yaml:"maxRetries,omitempty"`
+	RequestTimeout string `yaml:"requestTimeout,omitempty"`
+	RequestBudget  string `yaml:"requestBudget,omitempty"`
+	// CacheTTL overrides how long projects, boards and sprints are cached (see GetCacheTTLs),
+	// e.g. sprints=5m,boards=0
+	CacheTTL string `yaml:"cacheTTL,omitempty"`
+	// WarnAfter is the default of --warn-after: how long issues can stay in each status
+	// (see ParseWarnThresholds), e.g. In Progress=3,In Review=2,Blocked=5
+	WarnAfter string `yaml:"warnAfter,omitempty"`
+}
+
+// get returns the value of setting name in profile
+func (p *Profile) get(name string) string {
+	switch name {
+	case BaseURLSetting:
+		return p.BaseURL
+	case ProjectSetting:
+		return p.Project
+	case BoardSetting:
+		return p.Board
+	case UsernameSetting:
+		return p.Username
+	case PasswordSetting:
+		return p.Password
+	case AuthSetting:
+		return p.Auth
+	case TokenSetting:
+		return p.Token
+	case OAuthClientIDSetting:
+		return p.OAuthClientID
+	case OAuthClientSecretSetting:
+		return p.OAuthClientSecret
+	case OAuthPrivateKeySetting:
+		return p.OAuthPrivateKey
+	case SecretStoreSetting:
+		return p.SecretStore
+	case MaxRetriesSetting:
+		return p.MaxRetries
+	case RequestTimeoutSetting:
+		return p.RequestTimeout
+	case RequestBudgetSetting:
+		return p.RequestBudget
+	case CacheTTLSetting:
+		return p.CacheTTL
+	case WarnAfterSetting:
+		return p.WarnAfter
+	}
+	return ""
+}
+
+// activeSettings contains the config and profile in use
+var activeSettings = struct {
+	config      *Config
+	profileName string
+	profile     *Profile
+}{config: &Config{}}
+
+// UseProfile makes config and its profile profileName the source of all settings
+// not set with flags or env variables. If profileName is empty, the profile in
+// env variable JIRA_PROFILE is used or, if not set either, config defaultProfile.
+// No profile is used if none of them is set.
+func UseProfile(config *Config, profileName string) error {
+	if config == nil {
+		config = &Config{}
+	}
+	if profileName == "" {
+		profileName = os.Getenv(jiraProfile)
+	}
+	if profileName == "" {
+		profileName = config.DefaultProfile
+	}
+
+	var profile *Profile
+	if profileName != "" {
+		p, ok := config.Profiles[profileName]
+		if !ok {
+			return fmt.Errorf("profile %q not found in config file %s (available: %s)",
+				profileName, config.Path(), strings.Join(config.ProfileNames(), ", "))
+		}
+		profile = &p
+	}
+
+	activeSettings.config = config
+	activeSettings.profileName = profileName
+	activeSettings.profile = profile
+	return nil
+}
+
+// ActiveConfig returns the config in use (see UseProfile)
+func ActiveConfig() *Config {
+	return activeSettings.config
+}
+
+// ActiveProfile returns the name of the profile in use, if any (see UseProfile)
+func ActiveProfile() string {
+	return activeSettings.profileName
+}
+
+// LookupSetting returns the value of setting name and its source:
+// env variable first, then the profile in use.
+func LookupSetting(name string) (value, source string) {
+	if env, ok := settingEnvVariables[name]; ok {
+		if value, ok := os.LookupEnv(env); ok && value != "" {
+			return value, SourceEnv
+		}
+	}
+	if activeSettings.profile != nil {
+		if value := activeSettings.profile.get(name); value != "" {
+			return value, SourceProfile
+		}
+	}
+	return "", SourceUnset
+}
+
+// AuthSettings returns the settings needed by any command accessing Jira:
+// base URL and the credentials needed by the auth type in use
+func AuthSettings() []string {
+	authName, _ := LookupSetting(AuthSetting)
+	authType, err := ParseAuthType(authName)
+	if err != nil {
+		authType = AuthBasic
+	}
+	return append([]string{BaseURLSetting}, authType.requiredSettings()...)
+}
+
+// VerifySettings returns a MissingSettingsError listing the passed settings which are not set.
+// Secrets not set are looked up in the secret store in use, if any (see storedSecret).
+func VerifySettings(names ...string) error {
+	missing := make([]string, 0)
+	for _, name := range names {
+		if value, _ := LookupSetting(name); value != "" {
+			continue
+		}
+		if secretSettings[name] {
+			if found, _ := storedSecret(name); found {
+				continue
+			}
+		}
+		missing = append(missing, name)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &MissingSettingsError{Settings: missing, Profile: activeSettings.profileName,
+		ConfigFile: activeSettings.config.Path()}
+}
+
+// VerifyEnvVariables verifies base URL, project, board and credentials are set.
+//
+// Deprecated: settings can also come from a config file profile. Use VerifySettings.
+func VerifyEnvVariables(logger logr.Logger) error {
+	logger.Info("Verifying all needed settings are set")
+	return VerifySettings(append(AuthSettings(), ProjectSetting, BoardSetting)...)
+}
+
+// ValidateSettings verifies all settings needed by the auth type in use,
+// project and board are set and well formed
+func ValidateSettings() error {
+	authName, source := LookupSetting(AuthSetting)
+	if _, err := ParseAuthType(authName); err != nil {
+		return fmt.Errorf("%s (from %s): %w", AuthSetting, source, err)
+	}
+
+	if _, err := ActiveSecretStore(); err != nil {
+		return err
+	}
+
+	if _, err := GetRetryOptions(); err != nil {
+		return err
+	}
+
+	if _, err := GetCacheTTLs(); err != nil {
+		return err
+	}
+
+	if warnAfter, source := LookupSetting(WarnAfterSetting); warnAfter != "" {
+		if _, err := ParseWarnThresholds(warnAfter); err != nil {
+			return fmt.Errorf("%s (from %s): %w", WarnAfterSetting, source, err)
+		}
+	}
+
+	if err := VerifySettings(append(AuthSettings(), ProjectSetting, BoardSetting)...); err != nil {
+		return err
+	}
+
+	baseURL, source := LookupSetting(BaseURLSetting)
+	if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%s %q (from %s) is not a valid URL", BaseURLSetting, baseURL, source)
+	}
+
+	if encodedPassword, source := LookupSetting(PasswordSetting); encodedPassword != "" {
+		if _, err := decodePassword(encodedPassword); err != nil {
+			return fmt.Errorf("%s (from %s) is not base64 encoded: %v", PasswordSetting, source, err)
+		}
+	}
+
+	return nil
+}
+
+// SettingValue is a setting with its value and source.
+type SettingValue struct {
+	Name   string `json:"name" yaml:"name"`
+	Value  string `json:"value" yaml:"value"`
+	Source string `json:"source" yaml:"source"`
+	Env    string `json:"env" yaml:"env"`
+}
+
+// SettingsReport is the document displayed by DisplaySettings
+type SettingsReport struct {
+	ConfigFile string         `json:"configFile" yaml:"configFile"`
+	Profile    string         `json:"profile" yaml:"profile"`
+	Profiles   []string       `json:"profiles" yaml:"profiles"`
+	Settings   []SettingValue `json:"settings" yaml:"settings"`
+}
+
+// DisplaySettings writes the config file and profile in use and the value and
+// source of each setting. Secrets (password, token, OAuth client secret) are masked
+// and their value is never read from vault and helper secret stores.
+func DisplaySettings(w io.Writer, format OutputFormat) error {
+	secretStore, _ := LookupSetting(SecretStoreSetting)
+	report := &SettingsReport{
+		ConfigFile: activeSettings.config.Path(),
+		Profile:    activeSettings.profileName,
+		Profiles:   activeSettings.config.ProfileNames(),
+		Settings:   make([]SettingValue, len(settingNames)),
+	}
+	for i, name := range settingNames {
+		value, source := LookupSetting(name)
+		if secretSettings[name] && value != "" {
+			value = "********"
+		} else if secretSettings[name] && secretStore != "" {
+			if found, verified := storedSecret(name); !verified {
+				source = SourceSecretStoreUnverified
+			} else if found {
+				source = SourceSecretStore
+			}
+		}
+		report.Settings[i] = SettingValue{Name: name, Value: value, Source: source, Env: settingEnvVariables[name]}
+	}
+
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, report)
+	case OutputTable, "":
+	default:
+		return fmt.Errorf("output format %s is not supported for settings (supported: table, json, yaml)", format)
+	}
+
+	profile := report.Profile
+	if profile == "" {
+		profile = "(none)"
+	}
+	if _, err := fmt.Fprintf(w, "Config file: %s\nProfile: %s\n", report.ConfigFile, profile); err != nil {
+		return err
+	}
+	table := newTable(w, []string{"SETTING", "VALUE", "SOURCE", "ENV"})
+	for i := range report.Settings {
+		s := &report.Settings[i]
+		table.Append([]string{s.Name, s.Value, s.Source, s.Env})
+	}
+	table.Render()
+	return nil
+}
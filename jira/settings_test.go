@@ -0,0 +1,266 @@
+package jira
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+// testConfig has two profiles: dev, the default, sets all settings but the board
+const testConfig = `
+defaultProfile: dev
+profiles:
+  dev:
+    baseURL: https://jira-dev.example.com
+    project: CLOUDSTACK
+    username: dev
+    password: ZGV2
+  prod:
+    baseURL: https://jira.example.com
+    project: KUBE
+    board: Kube Scrum
+    username: prod
+    password: cHJvZA==
+`
+
+// writeConfig writes content to a config file in a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clearSettings unsets all env variables read by settings and restores
+// the settings in use at the end of the test
+func clearSettings(t *testing.T) {
+	t.Helper()
+	for _, env := range settingEnvVariables {
+		t.Setenv(env, "")
+	}
+	t.Setenv(jiraProfile, "")
+	saved := activeSettings
+	t.Cleanup(func() { activeSettings = saved })
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantProfiles []string
+		wantErr      string
+	}{
+		{name: "profiles", content: testConfig, wantProfiles: []string{"dev", "prod"}},
+		{name: "empty", content: "", wantProfiles: []string{}},
+		{name: "invalid yaml", content: "profiles: [dev", wantErr: "failed to parse config file"},
+		{name: "undefined default profile", content: "defaultProfile: qa\nprofiles:\n  dev: {project: KUBE}\n",
+			wantErr: `default profile "qa" is not defined`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			config, err := LoadConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Path() != path {
+				t.Errorf("got path %s, want %s", config.Path(), path)
+			}
+			if got := config.ProfileNames(); !reflect.DeepEqual(got, tt.wantProfiles) {
+				t.Errorf("got profiles %v, want %v", got, tt.wantProfiles)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(configPath, path)
+	config, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Path() != path || len(config.Profiles) != 0 {
+		t.Errorf("got config %+v, want an empty config loaded from %s", config, path)
+	}
+}
+
+func TestLookupSetting(t *testing.T) {
+	tests := []struct {
+		name string
+		// profile is passed to UseProfile, envProfile is the value of JIRA_PROFILE
+		profile    string
+		envProfile string
+		env        map[string]string
+		setting    string
+		wantValue  string
+		wantSource string
+	}{
+		{name: "default profile", setting: ProjectSetting, wantValue: "CLOUDSTACK", wantSource: SourceProfile},
+		{name: "profile", profile: "prod", setting: ProjectSetting, wantValue: "KUBE", wantSource: SourceProfile},
+		{name: "env profile", envProfile: "prod", setting: ProjectSetting, wantValue: "KUBE",
+			wantSource: SourceProfile},
+		{name: "profile over env profile", profile: "dev", envProfile: "prod", setting: ProjectSetting,
+			wantValue: "CLOUDSTACK", wantSource: SourceProfile},
+		{name: "env over profile", profile: "prod", env: map[string]string{jiraProject: "ENV"},
+			setting: ProjectSetting, wantValue: "ENV", wantSource: SourceEnv},
+		{name: "empty env is ignored", profile: "prod", env: map[string]string{jiraProject: ""},
+			setting: ProjectSetting, wantValue: "KUBE", wantSource: SourceProfile},
+		{name: "env only", env: map[string]string{jiraBoardName: "Env Board"}, setting: BoardSetting,
+			wantValue: "Env Board", wantSource: SourceEnv},
+		{name: "unset", setting: BoardSetting, wantValue: "", wantSource: SourceUnset},
+		{name: "unknown setting", setting: "colour", wantValue: "", wantSource: SourceUnset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearSettings(t)
+			t.Setenv(jiraProfile, tt.envProfile)
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+			config, err := LoadConfig(writeConfig(t, testConfig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := UseProfile(config, tt.profile); err != nil {
+				t.Fatalf("UseProfile: %v", err)
+			}
+
+			value, source := LookupSetting(tt.setting)
+			if value != tt.wantValue || source != tt.wantSource {
+				t.Errorf("got %q from %s, want %q from %s", value, source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestUseProfile(t *testing.T) {
+	clearSettings(t)
+	config, err := LoadConfig(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UseProfile(config, "qa"); err == nil || !strings.Contains(err.Error(), "available: dev, prod") {
+		t.Errorf("got error %v, want profile not found listing the available profiles", err)
+	}
+	if ActiveProfile() != "" {
+		t.Errorf("active profile changed to %q by a failed UseProfile", ActiveProfile())
+	}
+
+	if err := UseProfile(config, "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveProfile() != "prod" || ActiveConfig() != config {
+		t.Errorf("got profile %q of %s, want prod of %s", ActiveProfile(), ActiveConfig().Path(), config.Path())
+	}
+
+	// no profile: only env variables are used
+	if err := UseProfile(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if value, source := LookupSetting(ProjectSetting); source != SourceUnset {
+		t.Errorf("got project %q from %s without profile", value, source)
+	}
+}
+
+func TestVerifySettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     string
+		env         map[string]string
+		settings    []string
+		wantMissing []string
+	}{
+		{name: "all set", profile: "prod", settings: []string{BaseURLSetting, ProjectSetting, BoardSetting}},
+		{name: "missing in profile", profile: "dev", settings: []string{ProjectSetting, BoardSetting},
+			wantMissing: []string{BoardSetting}},
+		{name: "set with env", profile: "dev", env: map[string]string{jiraBoardName: "Env Board"},
+			settings: []string{ProjectSetting, BoardSetting}},
+		{name: "no profile", settings: []string{BaseURLSetting, UsernameSetting},
+			wantMissing: []string{BaseURLSetting, UsernameSetting}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearSettings(t)
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+			config := &Config{}
+			if tt.profile != "" {
+				var err error
+				if config, err = LoadConfig(writeConfig(t, testConfig)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := UseProfile(config, tt.profile); err != nil {
+				t.Fatal(err)
+			}
+
+			err := VerifySettings(tt.settings...)
+			if tt.wantMissing == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrMissingConfig) {
+				t.Errorf("got error %v, want ErrMissingConfig", err)
+			}
+			var missingErr *MissingSettingsError
+			if !errors.As(err, &missingErr) {
+				t.Fatalf("got error %T, want *MissingSettingsError", err)
+			}
+			if !reflect.DeepEqual(missingErr.Settings, tt.wantMissing) {
+				t.Errorf("got missing settings %v, want %v", missingErr.Settings, tt.wantMissing)
+			}
+			if missingErr.Profile != tt.profile || missingErr.ConfigFile != config.Path() {
+				t.Errorf("got profile %q in %q, want %q in %q", missingErr.Profile, missingErr.ConfigFile,
+					tt.profile, config.Path())
+			}
+			for _, name := range tt.wantMissing {
+				if env := settingEnvVariables[name]; !strings.Contains(err.Error(), env) {
+					t.Errorf("error %q does not mention env variable %s", err, env)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyEnvVariables(t *testing.T) {
+	clearSettings(t)
+	if err := UseProfile(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(jiraBaseURL, "https://jira.example.com")
+	t.Setenv(username, "mgianluc")
+	t.Setenv(password, "cGFzc3dvcmQ=")
+	t.Setenv(jiraProject, "CLOUDSTACK")
+
+	var missingErr *MissingSettingsError
+	if err := VerifyEnvVariables(logr.Discard()); !errors.As(err, &missingErr) ||
+		!reflect.DeepEqual(missingErr.Settings, []string{BoardSetting}) {
+		t.Errorf("got error %v, want board missing", err)
+	}
+
+	t.Setenv(jiraBoardName, "CloudStack Scrum")
+	if err := VerifyEnvVariables(logr.Discard()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
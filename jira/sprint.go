@@ -0,0 +1,215 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+// Sprint states, as returned by the agile API
+const (
+	SprintActive = "active"
+	SprintFuture = "future"
+	SprintClosed = "closed"
+)
+
+// sprintCountWorkers is the number of sprints whose issues are counted concurrently by DisplayJiraSprints
+const sprintCountWorkers = 4
+
+// SprintFilter selects sprints listed by GetJiraSprints
+type SprintFilter struct {
+	// States, if set, are the states of the sprints (SprintActive, SprintFuture, SprintClosed)
+	States []string
+	// Since, if set, excludes sprints which ended before it
+	Since time.Time
+}
+
+// matches returns true if sprint is selected by filter. States are filtered by Jira.
+func (f *SprintFilter) matches(sprint *Sprint) bool {
+	if f.Since.IsZero() {
+		return true
+	}
+	end := sprint.EndDate
+	if sprint.CompleteDate != nil {
+		end = sprint.CompleteDate
+	}
+	return end == nil || !end.Before(f.Since)
+}
+
+// ParseSprintStates parses a comma separated list of sprint states, e.g. "active,future"
+func ParseSprintStates(value string) ([]string, error) {
+	states := make([]string, 0)
+	for _, state := range strings.Split(value, ",") {
+		switch state = strings.ToLower(strings.TrimSpace(state)); state {
+		case SprintActive, SprintFuture, SprintClosed:
+			states = append(states, state)
+		default:
+			return nil, fmt.Errorf("unknown sprint state %q (supported: %s, %s, %s)", state,
+				SprintActive, SprintFuture, SprintClosed)
+		}
+	}
+	return states, nil
+}
+
+// ParseSince parses a date (e.g. 2022-04-01) or an age relative to now (e.g. 30d or 36h)
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+	if age, err := parseAge(value); err == nil {
+		return now.Add(-age), nil
+	}
+	return time.Time{}, fmt.Errorf("%q must be a date (e.g. 2022-04-01) or an age (e.g. 30d)", value)
+}
+
+// sprintIssueCounts are the number of issues and story points of a sprint not done and done
+type sprintIssueCounts struct {
+	openIssues, doneIssues int
+	openPoints, donePoints float64
+}
+
+// isDone returns true if issue status is one of doneStatuses (DefaultDoneStatuses if empty),
+// as in sprint reports
+func isDone(issue *jira.Issue, doneStatuses []string) bool {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return false
+	}
+	return isDoneStatus(issue.Fields.Status.Name, doneStatuses)
+}
+
+// countSprintIssues returns the issues and story points, not done and done, of sprint
+func countSprintIssues(ctx context.Context, jiraClient JiraAPI, sprint *Sprint, pointsColumn *Column,
+	doneStatuses []string, logger logr.Logger) (*sprintIssueCounts, error) {
+	fields := []string{"status"}
+	if pointsColumn != nil {
+		fields = append(fields, pointsColumn.FieldID)
+	}
+	issues, _, err := GetJiraIssues(ctx, jiraClient, fmt.Sprintf("sprint = %d", sprint.ID),
+		&QueryOptions{Fields: fields}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := &sprintIssueCounts{}
+	for i := range issues {
+		points := 0.0
+		if pointsColumn != nil {
+			points, _ = strconv.ParseFloat(pointsColumn.Value(&issues[i]), 64)
+		}
+		if isDone(&issues[i], doneStatuses) {
+			counts.doneIssues++
+			counts.donePoints += points
+		} else {
+			counts.openIssues++
+			counts.openPoints += points
+		}
+	}
+	return counts, nil
+}
+
+// daysRemaining returns the days until the end of an active sprint, rounded up, nil for other sprints
+func daysRemaining(sprint *Sprint, now time.Time) *int {
+	if sprint.State != SprintActive || sprint.EndDate == nil {
+		return nil
+	}
+	days := int(math.Ceil(sprint.EndDate.Sub(now).Hours() / 24))
+	if days < 0 {
+		days = 0
+	}
+	return &days
+}
+
+// countAllSprintIssues returns the counts of countSprintIssues for each of sprints, counting
+// up to sprintCountWorkers sprints at the same time. The first error stops the counting.
+func countAllSprintIssues(ctx context.Context, jiraClient JiraAPI, sprints []Sprint, pointsColumn *Column,
+	doneStatuses []string, logger logr.Logger) ([]*sprintIssueCounts, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	counts := make([]*sprintIssueCounts, len(sprints))
+	var firstErr error
+	var mu sync.Mutex
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < sprintCountWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				sprintCounts, err := countSprintIssues(ctx, jiraClient, &sprints[i], pointsColumn, doneStatuses, logger)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				counts[i] = sprintCounts
+			}
+		}()
+	}
+
+	for i := range sprints {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return counts, ctx.Err()
+}
+
+// DisplayJiraSprints displays the sprints of board boardID selected by filter, with their
+// dates, goal, days remaining and number of issues and story points not done and done.
+// Issues are done when their status is one of doneStatuses (DefaultDoneStatuses if empty).
+func DisplayJiraSprints(ctx context.Context, jiraClient JiraAPI, boardID string, filter *SprintFilter,
+	doneStatuses []string, options *DisplayOptions, logger logr.Logger) error {
+	if options == nil {
+		options = &DisplayOptions{}
+	}
+
+	sprints, err := GetJiraSprints(ctx, jiraClient, boardID, filter, logger)
+	if err != nil {
+		return err
+	}
+
+	var pointsColumn *Column
+	if columns, err := ResolveColumns(ctx, jiraClient, []string{"storyPoints"}, logger); err == nil {
+		pointsColumn = &columns[0]
+	} else {
+		logger.Info(fmt.Sprintf("Story points not available: %v", err))
+	}
+
+	counts, err := countAllSprintIssues(ctx, jiraClient, sprints, pointsColumn, doneStatuses, logger)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	list := &SprintList{Count: len(sprints), Sprints: make([]SprintRecord, len(sprints))}
+	for i := range sprints {
+		list.Sprints[i] = newSprintRecord(&sprints[i], counts[i], now)
+	}
+
+	if options.Template != nil {
+		return options.Template.Execute(options.writer(), list)
+	}
+	return writeSprints(options.writer(), options.Output, list)
+}
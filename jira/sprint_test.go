@@ -0,0 +1,243 @@
+package jira_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+
+	jirautils "github.com/gianlucam76/jira_utils/jira"
+	"github.com/gianlucam76/jira_utils/jira/fake"
+)
+
+// boardSprintsFixture has a sprint in each state. Sprint-1 was completed on March 10th, before its end date.
+const boardSprintsFixture = `
+projects:
+  - {id: "10000", key: CLOUDSTACK, name: CloudStack}
+boards:
+  - {id: 1, name: CloudStack Scrum, type: scrum, project: CLOUDSTACK}
+sprints:
+  1:
+    - {id: 1, name: Sprint-1, state: closed, startDate: 2022-03-01T09:00:00Z, endDate: 2022-03-15T09:00:00Z,
+       completeDate: 2022-03-10T09:00:00Z}
+    - {id: 2, name: Sprint-2, state: closed, startDate: 2022-03-15T10:00:00Z, endDate: 2022-03-29T09:00:00Z,
+       completeDate: 2022-03-29T09:00:00Z}
+    - {id: 3, name: Sprint-3, state: active, startDate: 2022-03-29T10:00:00Z, endDate: 2099-04-12T09:00:00Z}
+    - {id: 4, name: Sprint-4, state: future}
+issues:
+  - id: "20001"
+    key: CLOUDSTACK-1
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: Resolved}
+      customfield_10002: 3
+      sprint: {id: 3, name: Sprint-3, state: active}
+  - id: "20002"
+    key: CLOUDSTACK-2
+    fields:
+      project: {key: CLOUDSTACK}
+      status: {name: In Progress}
+      customfield_10002: 2
+      sprint: {id: 3, name: Sprint-3, state: active}
+fields:
+  - id: customfield_10002
+    name: Story Points
+    custom: true
+    schema: {type: number, custom: com.atlassian.jira.plugin.system.customfieldtypes:float, customId: 10002}
+`
+
+func newBoardSprintsClient(t *testing.T) *fake.Client {
+	fixture, err := fake.ParseFixture([]byte(boardSprintsFixture))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return fake.NewClientFromFixture(fixture)
+}
+
+// displaySprints returns the sprints displayed by DisplayJiraSprints as json
+func displaySprints(t *testing.T, jiraClient jirautils.JiraAPI, filter *jirautils.SprintFilter) (*jirautils.SprintList,
+	error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := jirautils.DisplayJiraSprints(context.TODO(), jiraClient, "1", filter, nil,
+		&jirautils.DisplayOptions{Output: jirautils.OutputJSON, Writer: &out}, logr.Discard())
+	if err != nil {
+		if out.Len() > 0 {
+			t.Errorf("got output %q with error %v", out.String(), err)
+		}
+		return nil, err
+	}
+	var list jirautils.SprintList
+	if err := json.Unmarshal(out.Bytes(), &list); err != nil {
+		t.Fatalf("invalid output %q: %v", out.String(), err)
+	}
+	return &list, nil
+}
+
+func TestDisplayJiraSprintsFilter(t *testing.T) {
+	since := time.Date(2022, 3, 12, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter *jirautils.SprintFilter
+		want   []string
+	}{
+		{name: "no filter", want: []string{"Sprint-1", "Sprint-2", "Sprint-3", "Sprint-4"}},
+		{name: "active and future", filter: &jirautils.SprintFilter{
+			States: []string{jirautils.SprintActive, jirautils.SprintFuture}}, want: []string{"Sprint-3", "Sprint-4"}},
+		{name: "closed", filter: &jirautils.SprintFilter{States: []string{jirautils.SprintClosed}},
+			want: []string{"Sprint-1", "Sprint-2"}},
+		// Sprint-1 ends after since, but was completed before
+		{name: "since", filter: &jirautils.SprintFilter{Since: since},
+			want: []string{"Sprint-2", "Sprint-3", "Sprint-4"}},
+		{name: "closed since", filter: &jirautils.SprintFilter{States: []string{jirautils.SprintClosed}, Since: since},
+			want: []string{"Sprint-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := displaySprints(t, newBoardSprintsClient(t), tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			names := make([]string, len(list.Sprints))
+			for i := range list.Sprints {
+				names[i] = list.Sprints[i].Name
+			}
+			if !reflect.DeepEqual(names, tt.want) || list.Count != len(tt.want) {
+				t.Errorf("got %d sprints %v, want %v", list.Count, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayJiraSprintsCounts(t *testing.T) {
+	list, err := displaySprints(t, newBoardSprintsClient(t),
+		&jirautils.SprintFilter{States: []string{jirautils.SprintActive}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Sprints) != 1 {
+		t.Fatalf("got %d sprints, want 1", len(list.Sprints))
+	}
+	got := list.Sprints[0]
+	if got.OpenIssues != 1 || got.OpenPoints != 2 || got.DoneIssues != 1 || got.DonePoints != 3 {
+		t.Errorf("got %d issues (%g points) open and %d (%g points) done, want 1 (2) and 1 (3)",
+			got.OpenIssues, got.OpenPoints, got.DoneIssues, got.DonePoints)
+	}
+	if got.DaysRemaining == nil {
+		t.Errorf("got no days remaining for the active sprint")
+	}
+}
+
+// failingSearchClient fails the searches of the issues of sprints in failing
+type failingSearchClient struct {
+	*fake.Client
+	failing map[string]error
+}
+
+func (c *failingSearchClient) SearchIssues(ctx context.Context, jql string, options *jira.SearchOptions) ([]jira.Issue,
+	int, error) {
+	for sprint, err := range c.failing {
+		if strings.HasPrefix(jql, "sprint = "+sprint) {
+			return nil, 0, err
+		}
+	}
+	return c.Client.SearchIssues(ctx, jql, options)
+}
+
+func TestDisplayJiraSprintsCountError(t *testing.T) {
+	errSprint2 := errors.New("sprint 2 failed")
+	errSprint3 := errors.New("sprint 3 failed")
+	tests := []struct {
+		name    string
+		failing map[string]error
+		want    []error
+	}{
+		{name: "one sprint fails", failing: map[string]error{"2": errSprint2}, want: []error{errSprint2}},
+		// either error is returned, depending on which worker fails first
+		{name: "two sprints fail", failing: map[string]error{"2": errSprint2, "3": errSprint3},
+			want: []error{errSprint2, errSprint3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &failingSearchClient{Client: newBoardSprintsClient(t), failing: tt.failing}
+			_, err := displaySprints(t, client, nil)
+			found := false
+			for _, want := range tt.want {
+				found = found || errors.Is(err, want)
+			}
+			if !found {
+				t.Errorf("got error %v, want one of %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayJiraSprintsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &failingSearchClient{Client: newBoardSprintsClient(t)}
+	err := jirautils.DisplayJiraSprints(ctx, client, "1", nil, nil,
+		&jirautils.DisplayOptions{Output: jirautils.OutputJSON, Writer: &bytes.Buffer{}}, logr.Discard())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestParseSprintStates(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "active", want: []string{jirautils.SprintActive}},
+		{value: "Active, FUTURE", want: []string{jirautils.SprintActive, jirautils.SprintFuture}},
+		{value: "closed,future", want: []string{jirautils.SprintClosed, jirautils.SprintFuture}},
+		{value: "open", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := jirautils.ParseSprintStates(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSprintStates(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSprintStates(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2022, 4, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2022-04-01", want: time.Date(2022, 4, 1, 0, 0, 0, 0, time.Local)},
+		{value: "30d", want: now.AddDate(0, 0, -30)},
+		{value: "36h", want: now.Add(-36 * time.Hour)},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := jirautils.ParseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSince(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
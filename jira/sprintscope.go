@@ -0,0 +1,267 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Kinds of sprint scope changes, as displayed by csv and tsv output
+const (
+	changeCarriedOver = "carriedOver"
+	changeAdded       = "added"
+	changeRemoved     = "removed"
+	changeUnresolved  = "unresolved"
+)
+
+// SprintScope is the document displayed by DisplaySprintScope with json and yaml output.
+type SprintScope struct {
+	Sprint    string    `json:"sprint" yaml:"sprint"`
+	State     string    `json:"state" yaml:"state"`
+	StartDate time.Time `json:"startDate" yaml:"startDate"`
+	EndDate   time.Time `json:"endDate" yaml:"endDate"`
+	// Count is the number of issues in the sprint when it ended (or now, if active)
+	Count int `json:"count" yaml:"count"`
+	// CarriedOver are the issues that were in previous sprints
+	CarriedOver []SprintScopeIssue `json:"carriedOver" yaml:"carriedOver"`
+	// Added and Removed are the issues added to or removed from the sprint after it started
+	Added   []SprintScopeIssue `json:"added" yaml:"added"`
+	Removed []SprintScopeIssue `json:"removed" yaml:"removed"`
+	// Unresolved are the issues in the sprint and not done when it ended (or now, if active)
+	Unresolved []SprintScopeIssue `json:"unresolved" yaml:"unresolved"`
+	Assignees  []AssigneeScope    `json:"assignees" yaml:"assignees"`
+}
+
+// SprintScopeIssue is an issue carried over, added, removed or unresolved
+type SprintScopeIssue struct {
+	Key      string  `json:"key" yaml:"key"`
+	Summary  string  `json:"summary" yaml:"summary"`
+	Assignee string  `json:"assignee" yaml:"assignee"`
+	Status   string  `json:"status" yaml:"status"`
+	Points   float64 `json:"points" yaml:"points"`
+	// Date is when the issue was added or removed (added and removed issues only)
+	Date *time.Time `json:"date,omitempty" yaml:"date,omitempty"`
+	// PreviousSprints are the sprints the issue was in before (carried over issues only)
+	PreviousSprints []string `json:"previousSprints,omitempty" yaml:"previousSprints,omitempty"`
+}
+
+// AssigneeScope is the number of issues of an assignee (current one) in each SprintScope list
+type AssigneeScope struct {
+	Assignee    string `json:"assignee" yaml:"assignee"`
+	Issues      int    `json:"issues" yaml:"issues"`
+	CarriedOver int    `json:"carriedOver" yaml:"carriedOver"`
+	Added       int    `json:"added" yaml:"added"`
+	Removed     int    `json:"removed" yaml:"removed"`
+	Unresolved  int    `json:"unresolved" yaml:"unresolved"`
+}
+
+// previousSprints returns the sprints, other than sprintName, in the Sprint field of the issue when it
+// entered sprintName. Jira keeps closed sprints in the field, while an issue moved between open sprints
+// (e.g. from a parallel sprint, or later to a sprint of another board) leaves the previous one: these
+// are the sprints the issue was carried over from.
+func (i *sprintIssue) previousSprints(sprintName string) []string {
+	names := make([]string, 0)
+	for j := range i.sprints {
+		if !inSprint(i.sprints[j].Value, sprintName) {
+			continue
+		}
+		for _, name := range strings.Split(i.sprints[j].Value, ",") {
+			if name = strings.TrimSpace(name); name != "" && name != sprintName {
+				names = append(names, name)
+			}
+		}
+		break
+	}
+	return names
+}
+
+// GetSprintScope returns, for sprint sprintName of board boardID (the active sprint if sprintName is
+// empty), the issues carried over from previous sprints, added or removed after the sprint started and
+// unresolved when it ended, rebuilt from the Sprint field history in the issue changelogs.
+// Only DoneStatuses and Project of options are used.
+func GetSprintScope(ctx context.Context, jiraClient JiraAPI, boardID, sprintName string, options *BurndownOptions,
+	logger logr.Logger) (*SprintScope, error) {
+	scopeOptions := options.withDefaults()
+
+	sprint, err := getSprint(ctx, jiraClient, boardID, sprintName, logger)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := sprintPeriod(sprint)
+	if err != nil {
+		return nil, err
+	}
+	issues, _, err := getSprintIssues(ctx, jiraClient, sprint, scopeOptions.Project, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	last := end
+	if now := time.Now(); now.Before(last) {
+		last = now
+	}
+
+	scope := &SprintScope{Sprint: sprint.Name, State: sprint.State, StartDate: start, EndDate: *sprint.EndDate,
+		CarriedOver: make([]SprintScopeIssue, 0), Added: make([]SprintScopeIssue, 0),
+		Removed: make([]SprintScopeIssue, 0), Unresolved: make([]SprintScopeIssue, 0)}
+	assignees := map[string]*AssigneeScope{}
+	for i := range issues {
+		issue := &issues[i]
+		record := SprintScopeIssue{Key: issue.key, Summary: issue.summary, Assignee: issue.assignee,
+			Status: issue.status, Points: issue.pointsAt(last)}
+		counts, ok := assignees[issue.assignee]
+		if !ok {
+			counts = &AssigneeScope{Assignee: issue.assignee}
+			assignees[issue.assignee] = counts
+		}
+		member := issue.memberAt(sprint.Name, last)
+		if member {
+			scope.Count++
+			counts.Issues++
+		}
+
+		// issues removed from the sprint were not carried over to it
+		if previous := issue.previousSprints(sprint.Name); !issue.removed && len(previous) > 0 {
+			carried := record
+			carried.PreviousSprints = previous
+			scope.CarriedOver = append(scope.CarriedOver, carried)
+			counts.CarriedOver++
+		}
+
+		added, removed := appendScopeChanges(nil, nil, issue, sprint.Name, start, last)
+		for j := range added {
+			change := record
+			change.Date = &added[j].Date
+			scope.Added = append(scope.Added, change)
+			counts.Added++
+		}
+		for j := range removed {
+			change := record
+			change.Date = &removed[j].Date
+			scope.Removed = append(scope.Removed, change)
+			counts.Removed++
+		}
+
+		if member && !issue.doneAt(scopeOptions.DoneStatuses, last) {
+			scope.Unresolved = append(scope.Unresolved, record)
+			counts.Unresolved++
+		}
+	}
+
+	scope.Assignees = make([]AssigneeScope, 0, len(assignees))
+	for _, counts := range assignees {
+		scope.Assignees = append(scope.Assignees, *counts)
+	}
+	sort.Slice(scope.Assignees, func(i, j int) bool { return scope.Assignees[i].Assignee < scope.Assignees[j].Assignee })
+	return scope, nil
+}
+
+// DisplaySprintScope displays the issues of sprint sprintName of board boardID (the active sprint if
+// sprintName is empty) carried over, added, removed and unresolved, with a breakdown per assignee
+func DisplaySprintScope(ctx context.Context, jiraClient JiraAPI, boardID, sprintName string,
+	scopeOptions *BurndownOptions, options *DisplayOptions, logger logr.Logger) error {
+	if options == nil {
+		options = &DisplayOptions{}
+	}
+
+	scope, err := GetSprintScope(ctx, jiraClient, boardID, sprintName, scopeOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	if options.Template != nil {
+		return options.Template.Execute(options.writer(), scope)
+	}
+	return writeSprintScope(options.writer(), options.Output, scope)
+}
+
+// sprintScopeHeaders are the csv/tsv headers of an issue of a SprintScope list
+var sprintScopeHeaders = []string{"change", "key", "summary", "assignee", "status", "points", "date", "previousSprints"}
+
+func (i *SprintScopeIssue) values(change string) []string {
+	return []string{change, i.Key, i.Summary, i.Assignee, i.Status, formatNumber(i.Points), formatTime(i.Date),
+		strings.Join(i.PreviousSprints, ",")}
+}
+
+// detail returns when the issue was added or removed, or the sprints it was carried over from
+func (i *SprintScopeIssue) detail() string {
+	if i.Date != nil {
+		return formatDate(*i.Date)
+	}
+	if len(i.PreviousSprints) > 0 {
+		return fmt.Sprintf("%d: %s", len(i.PreviousSprints), strings.Join(i.PreviousSprints, ", "))
+	}
+	return ""
+}
+
+// writeSprintScope writes scope in the passed format
+func writeSprintScope(w io.Writer, format OutputFormat, scope *SprintScope) error {
+	lists := []struct {
+		change string
+		name   string
+		issues []SprintScopeIssue
+	}{
+		{changeCarriedOver, "carried over", scope.CarriedOver},
+		{changeAdded, "added", scope.Added},
+		{changeRemoved, "removed", scope.Removed},
+		{changeUnresolved, "unresolved", scope.Unresolved},
+	}
+
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, scope)
+	case OutputCSV, OutputTSV:
+		rows := make([][]string, 0)
+		for _, list := range lists {
+			for i := range list.issues {
+				rows = append(rows, list.issues[i].values(list.change))
+			}
+		}
+		return writeSeparatedValues(w, format, sprintScopeHeaders, rows)
+	}
+
+	headers := []string{"CHANGE", "KEY", "ASSIGNEE", "STATUS", "POINTS", "DETAIL"}
+	rows := make([][]string, 0)
+	for _, list := range lists {
+		for i := range list.issues {
+			issue := &list.issues[i]
+			rows = append(rows, []string{list.name, issue.Key, issue.Assignee, issue.Status,
+				formatNumber(issue.Points), issue.detail()})
+		}
+	}
+	assigneeHeaders := []string{"ASSIGNEE", "ISSUES", "CARRIED OVER", "ADDED", "REMOVED", "UNRESOLVED"}
+	assigneeRows := make([][]string, len(scope.Assignees))
+	for i := range scope.Assignees {
+		a := &scope.Assignees[i]
+		assigneeRows[i] = []string{a.Assignee, fmt.Sprintf("%d", a.Issues), fmt.Sprintf("%d", a.CarriedOver),
+			fmt.Sprintf("%d", a.Added), fmt.Sprintf("%d", a.Removed), fmt.Sprintf("%d", a.Unresolved)}
+	}
+	caption := fmt.Sprintf("Sprint %s (%s), %s to %s: %d issues, %d carried over, %d added, %d removed, %d unresolved",
+		scope.Sprint, scope.State, formatDate(scope.StartDate), formatDate(scope.EndDate), scope.Count,
+		len(scope.CarriedOver), len(scope.Added), len(scope.Removed), len(scope.Unresolved))
+
+	if format == OutputMarkdown {
+		if err := writeMarkdown(w, headers, rows, ""); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+		return writeMarkdown(w, assigneeHeaders, assigneeRows, caption)
+	}
+
+	table := newTable(w, headers)
+	table.AppendBulk(rows)
+	table.Render()
+
+	assigneeTable := newTable(w, assigneeHeaders)
+	assigneeTable.AppendBulk(assigneeRows)
+	assigneeTable.Render()
+	_, err := fmt.Fprintln(w, caption)
+	return err
+}
@@ -0,0 +1,42 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPreviousSprints(t *testing.T) {
+	start := time.Date(2022, 3, 15, 10, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	end := day(14)
+	created := day(-30)
+
+	tests := []struct {
+		name    string
+		sprints []fieldInterval
+		want    []string
+	}{
+		{name: "in the sprint only", sprints: intervals(end, []time.Time{created}, "Sprint-2"), want: []string{}},
+		{name: "carried over", sprints: intervals(end, []time.Time{created, day(-1)}, "Sprint-1", "Sprint-1, Sprint-2"),
+			want: []string{"Sprint-1"}},
+		{name: "carried over twice", sprints: intervals(end, []time.Time{created, day(-15), day(-1)},
+			"Sprint-0", "Sprint-0, Sprint-1", "Sprint-0, Sprint-1, Sprint-2"), want: []string{"Sprint-0", "Sprint-1"}},
+		{name: "moved from a parallel sprint", sprints: intervals(end, []time.Time{created, day(1)},
+			"Parallel-1", "Sprint-2"), want: []string{}},
+		{name: "moved later to a sprint of another board", sprints: intervals(end, []time.Time{created, day(2)},
+			"Sprint-2", "Kube-5"), want: []string{}},
+		{name: "carried over to the next sprint", sprints: intervals(end, []time.Time{created, day(14)},
+			"Sprint-2", "Sprint-2, Sprint-3"), want: []string{}},
+		{name: "never in the sprint", sprints: intervals(end, []time.Time{created}, "Sprint-1"), want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &sprintIssue{key: "A-1", sprints: tt.sprints}
+			if got := issue.previousSprints("Sprint-2"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
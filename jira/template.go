@@ -0,0 +1,196 @@
+package jira
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/fatih/color"
+)
+
+// templateColors are the colors supported by the colorize template function
+var templateColors = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+// ParseTemplate parses text as a Go text/template used to display results.
+// Template is executed once against the whole result set (an IssueList,
+// a SprintList or a BoardList), so issues are accessed with {{range .Issues}}.
+// Besides the text/template builtins, these functions are available:
+//   - age <time>: time elapsed since time, e.g. "3d" or "5h"
+//   - daysInStatus <issue>: days since issue moved to its current status
+//   - colorize <color> <value>: value in color (red, green, yellow, blue, ...)
+//   - truncate <length> <value>: value truncated to length characters
+//   - join <separator> <list>: list elements joined by separator
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs()).Parse(text)
+}
+
+// ParseTemplateFile parses the content of file path as a Go text/template.
+// See ParseTemplate.
+func ParseTemplateFile(path string) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplate(path, string(content))
+}
+
+// TemplateNeedsChangelog returns true if tmpl, or any template it defines,
+// calls a template function requiring the issue changelog
+func TemplateNeedsChangelog(tmpl *template.Template) bool {
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && callsFunction(t.Tree.Root, "daysInStatus") {
+			return true
+		}
+	}
+	return false
+}
+
+// callsFunction returns true if the parse tree rooted at node calls function name
+func callsFunction(node parse.Node, name string) bool {
+	switch n := node.(type) {
+	case *parse.IdentifierNode:
+		return n.Ident == name
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if callsFunction(child, name) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return callsFunction(n.Pipe, name)
+	case *parse.TemplateNode:
+		return callsFunction(n.Pipe, name)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if callsFunction(cmd, name) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if callsFunction(arg, name) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return callsFunction(n.Node, name)
+	case *parse.IfNode:
+		return callsBranch(&n.BranchNode, name)
+	case *parse.RangeNode:
+		return callsBranch(&n.BranchNode, name)
+	case *parse.WithNode:
+		return callsBranch(&n.BranchNode, name)
+	}
+	return false
+}
+
+func callsBranch(n *parse.BranchNode, name string) bool {
+	return callsFunction(n.Pipe, name) || callsFunction(n.List, name) || callsFunction(n.ElseList, name)
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"age":          templateAge,
+		"daysInStatus": templateDaysInStatus,
+		"colorize":     templateColorize,
+		"truncate":     templateTruncate,
+		"join":         templateJoin,
+	}
+}
+
+func templateAge(t interface{}) (string, error) {
+	var since time.Time
+	switch v := t.(type) {
+	case time.Time:
+		since = v
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		since = *v
+	case jira.Time:
+		since = time.Time(v)
+	default:
+		return "", fmt.Errorf("age: unsupported type %T", t)
+	}
+	if since.IsZero() {
+		return "", nil
+	}
+
+	elapsed := time.Since(since)
+	if elapsed >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(elapsed.Hours()/24)), nil
+	}
+	return fmt.Sprintf("%dh", int(elapsed.Hours())), nil
+}
+
+func templateDaysInStatus(issue interface{}) (int, error) {
+	var i *jira.Issue
+	switch v := issue.(type) {
+	case IssueRecord:
+		i = v.Issue
+	case *IssueRecord:
+		i = v.Issue
+	case jira.Issue:
+		i = &v
+	case *jira.Issue:
+		i = v
+	default:
+		return 0, fmt.Errorf("daysInStatus: unsupported type %T", issue)
+	}
+	if i == nil {
+		return 0, nil
+	}
+	return int(time.Since(StatusSince(i)).Hours() / 24), nil
+}
+
+func templateColorize(name string, value interface{}) (string, error) {
+	attribute, ok := templateColors[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("colorize: unknown color %q", name)
+	}
+	return color.New(attribute).Sprint(value), nil
+}
+
+func templateTruncate(length int, value interface{}) string {
+	s := []rune(fmt.Sprint(value))
+	if len(s) <= length {
+		return string(s)
+	}
+	if length <= 3 {
+		return string(s[:length])
+	}
+	return string(s[:length-3]) + "..."
+}
+
+func templateJoin(separator string, list interface{}) (string, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: unsupported type %T", list)
+	}
+	elems := make([]string, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elems[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(elems, separator), nil
+}
@@ -0,0 +1,290 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+// Dimensions of the time-in-status report: time spent in each status or with each assignee
+const (
+	ByStatus   = "status"
+	ByAssignee = "assignee"
+)
+
+// unassigned is the assignee name used for periods without assignee
+const unassigned = "Unassigned"
+
+// TimeInStatusReport is the document displayed by DisplayTimeInStatus with json and yaml output.
+// Durations are in days.
+type TimeInStatusReport struct {
+	JQL   string `json:"jql" yaml:"jql"`
+	Count int    `json:"count" yaml:"count"`
+	// Statuses and Assignees are all statuses and assignees found, in order of first appearance
+	Statuses  []string            `json:"statuses" yaml:"statuses"`
+	Assignees []string            `json:"assignees" yaml:"assignees"`
+	Issues    []IssueTimeInStatus `json:"issues" yaml:"issues"`
+	// StatusStats and AssigneeStats aggregate the time spent in each status and with each assignee
+	StatusStats   []DurationStats `json:"statusStats" yaml:"statusStats"`
+	AssigneeStats []DurationStats `json:"assigneeStats" yaml:"assigneeStats"`
+}
+
+// IssueTimeInStatus is the time an issue spent in each status and with each assignee, in days
+type IssueTimeInStatus struct {
+	Key       string             `json:"key" yaml:"key"`
+	Summary   string             `json:"summary" yaml:"summary"`
+	Status    string             `json:"status" yaml:"status"`
+	Statuses  map[string]float64 `json:"statuses" yaml:"statuses"`
+	Assignees map[string]float64 `json:"assignees" yaml:"assignees"`
+}
+
+// DurationStats are the mean, median, 90th percentile and total of the days spent
+// in a status (or with an assignee) by the Issues that were in it
+type DurationStats struct {
+	Name   string  `json:"name" yaml:"name"`
+	Issues int     `json:"issues" yaml:"issues"`
+	Mean   float64 `json:"mean" yaml:"mean"`
+	Median float64 `json:"median" yaml:"median"`
+	P90    float64 `json:"p90" yaml:"p90"`
+	Total  float64 `json:"total" yaml:"total"`
+}
+
+// ValidateTimeInStatusBy returns an error if by is not a dimension of the time-in-status report
+func ValidateTimeInStatusBy(by string) error {
+	if by != ByStatus && by != ByAssignee {
+		return fmt.Errorf("unsupported value %q: must be %s or %s", by, ByStatus, ByAssignee)
+	}
+	return nil
+}
+
+// GetTimeInStatus returns how long each issue matching jql spent in every status and with
+// every assignee, from its creation until now, walking the issue changelog
+func GetTimeInStatus(ctx context.Context, jiraClient JiraAPI, jql string, options *QueryOptions,
+	logger logr.Logger) (*TimeInStatusReport, error) {
+	queryOptions := QueryOptions{}
+	if options != nil {
+		queryOptions = *options
+	}
+	queryOptions.Expand = addExpand(queryOptions.Expand, "changelog")
+	queryOptions.Fields = []string{"summary", "status", "assignee", "created"}
+
+	issues, _, err := GetJiraIssues(ctx, jiraClient, jql, &queryOptions, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetchChangelogs(ctx, jiraClient, issues, func(*jira.Issue) bool { return true }, logger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &TimeInStatusReport{JQL: jql, Count: len(issues), Statuses: make([]string, 0),
+		Assignees: make([]string, 0), Issues: make([]IssueTimeInStatus, len(issues))}
+	statusDays := map[string][]float64{}
+	assigneeDays := map[string][]float64{}
+	for i := range issues {
+		issue := &issues[i]
+		record := IssueTimeInStatus{Key: issue.Key, Status: "N/A"}
+		currentAssignee := unassigned
+		if issue.Fields != nil {
+			record.Summary = issue.Fields.Summary
+			if issue.Fields.Status != nil {
+				record.Status = issue.Fields.Status.Name
+			}
+			currentAssignee = assigneeName(issue.Fields.Assignee)
+		}
+
+		record.Statuses = sumIntervals(fieldHistory(issue, statusField, record.Status, now))
+		record.Assignees = sumIntervals(fieldHistory(issue, assigneeField, currentAssignee, now))
+		// periods without assignee have an empty value in the changelog
+		if days, ok := record.Assignees[""]; ok {
+			record.Assignees[unassigned] += days
+			delete(record.Assignees, "")
+		}
+
+		report.Statuses = appendNames(report.Statuses, statusDays, record.Statuses)
+		report.Assignees = appendNames(report.Assignees, assigneeDays, record.Assignees)
+		report.Issues[i] = record
+	}
+
+	report.StatusStats = durationStats(report.Statuses, statusDays)
+	report.AssigneeStats = durationStats(report.Assignees, assigneeDays)
+	return report, nil
+}
+
+// DisplayTimeInStatus displays how long each issue matching jql spent in every status and with
+// every assignee, and per status (or per assignee) mean, median and 90th percentile.
+// Table, markdown, csv and tsv output show the dimension by (status or assignee).
+func DisplayTimeInStatus(ctx context.Context, jiraClient JiraAPI, jql, by string, options *DisplayOptions,
+	logger logr.Logger) error {
+	if options == nil {
+		options = &DisplayOptions{}
+	}
+	if err := ValidateTimeInStatusBy(by); err != nil {
+		return err
+	}
+
+	report, err := GetTimeInStatus(ctx, jiraClient, jql, &options.QueryOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	if options.Template != nil {
+		return options.Template.Execute(options.writer(), report)
+	}
+	return writeTimeInStatus(options.writer(), options.Output, report, by)
+}
+
+// assigneeName returns the name of user as shown in the changelog, Unassigned if not set
+func assigneeName(user *jira.User) string {
+	switch {
+	case user == nil:
+		return unassigned
+	case user.DisplayName != "":
+		return user.DisplayName
+	}
+	return user.Name
+}
+
+// sumIntervals returns the days spent with each value
+func sumIntervals(intervals []fieldInterval) map[string]float64 {
+	days := map[string]float64{}
+	for i := range intervals {
+		days[intervals[i].Value] += intervals[i].End.Sub(intervals[i].Start).Hours() / 24
+	}
+	for value := range days {
+		days[value] = roundDays(days[value])
+	}
+	return days
+}
+
+// appendNames adds days to all, keyed by name, and returns names with the names not seen yet appended
+func appendNames(names []string, all map[string][]float64, days map[string]float64) []string {
+	newNames := make([]string, 0)
+	for name := range days {
+		if _, ok := all[name]; !ok {
+			newNames = append(newNames, name)
+		}
+		all[name] = append(all[name], days[name])
+	}
+	sort.Strings(newNames)
+	return append(names, newNames...)
+}
+
+// durationStats returns the DurationStats of each name
+func durationStats(names []string, all map[string][]float64) []DurationStats {
+	stats := make([]DurationStats, len(names))
+	for i, name := range names {
+		days := append([]float64{}, all[name]...)
+		sort.Float64s(days)
+		total := 0.0
+		for _, d := range days {
+			total += d
+		}
+		stats[i] = DurationStats{Name: name, Issues: len(days), Total: roundDays(total),
+			Mean: roundDays(total / float64(len(days))), Median: roundDays(percentile(days, 50)),
+			P90: roundDays(percentile(days, 90))}
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// roundDays rounds days to 2 decimals
+func roundDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
+
+// formatDays returns days as displayed in tables, e.g. 2.5d, or an empty string if 0
+func formatDays(days float64) string {
+	if days == 0 {
+		return ""
+	}
+	return formatNumber(math.Round(days*10)/10) + "d"
+}
+
+// writeTimeInStatus writes report in the passed format
+func writeTimeInStatus(w io.Writer, format OutputFormat, report *TimeInStatusReport, by string) error {
+	names, stats := report.Statuses, report.StatusStats
+	if by == ByAssignee {
+		names, stats = report.Assignees, report.AssigneeStats
+	}
+	issueDays := func(r *IssueTimeInStatus) map[string]float64 {
+		if by == ByAssignee {
+			return r.Assignees
+		}
+		return r.Statuses
+	}
+
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, report)
+	case OutputCSV, OutputTSV:
+		headers := append([]string{"key", "summary", "status"}, names...)
+		rows := make([][]string, len(report.Issues))
+		for i := range report.Issues {
+			record := &report.Issues[i]
+			rows[i] = []string{record.Key, record.Summary, record.Status}
+			for _, name := range names {
+				rows[i] = append(rows[i], formatNumber(issueDays(record)[name]))
+			}
+		}
+		return writeSeparatedValues(w, format, headers, rows)
+	}
+
+	headers := append([]string{"KEY", "STATUS"}, names...)
+	rows := make([][]string, len(report.Issues))
+	for i := range report.Issues {
+		record := &report.Issues[i]
+		rows[i] = []string{record.Key, record.Status}
+		for _, name := range names {
+			rows[i] = append(rows[i], formatDays(issueDays(record)[name]))
+		}
+	}
+	statsHeaders := []string{strings.ToUpper(by), "ISSUES", "MEAN", "MEDIAN", "P90", "TOTAL"}
+	statsRows := make([][]string, len(stats))
+	for i := range stats {
+		s := &stats[i]
+		statsRows[i] = []string{s.Name, fmt.Sprintf("%d", s.Issues), formatDays(s.Mean), formatDays(s.Median),
+			formatDays(s.P90), formatDays(s.Total)}
+	}
+	caption := fmt.Sprintf("Time in each %s of %d issues, from creation until now", by, report.Count)
+
+	if format == OutputMarkdown {
+		if err := writeMarkdown(w, headers, rows, ""); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+		return writeMarkdown(w, statsHeaders, statsRows, caption)
+	}
+
+	table := newTable(w, headers)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+	table.Render()
+
+	statsTable := newTable(w, statsHeaders)
+	statsTable.AppendBulk(statsRows)
+	statsTable.Render()
+	_, err := fmt.Fprintln(w, caption)
+	return err
+}
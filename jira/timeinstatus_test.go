@@ -0,0 +1,26 @@
+package jira
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{nil, 50, 0},
+		{[]float64{4}, 90, 4},
+		{[]float64{1, 2, 3}, 50, 2},
+		{[]float64{1, 2, 3, 4}, 50, 2.5},
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 90, 9.1},
+		{[]float64{2, 10}, 0, 2},
+		{[]float64{2, 10}, 100, 10},
+		{[]float64{2, 10}, 25, 4},
+	}
+
+	for _, tt := range tests {
+		if got := roundDays(percentile(tt.sorted, tt.p)); got != tt.want {
+			t.Errorf("percentile(%v, %g) = %g, want %g", tt.sorted, tt.p, got, tt.want)
+		}
+	}
+}
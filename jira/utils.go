@@ -3,42 +3,43 @@ package jira
 import (
 	"encoding/base64"
 	"fmt"
-	"os"
 
 	"github.com/go-logr/logr"
 )
 
-func GetUsername(logger logr.Logger) string {
-	user, ok := os.LookupEnv(username)
-	if !ok {
-		logger.Info(fmt.Sprintf("Env variable %s supposed to contain username not found", username))
-		panic(1)
-	}
-
-	if user == "" {
+// GetUsername returns the username setting (see LookupSetting).
+// Returns an error matching ErrMissingConfig if not set.
+func GetUsername(logger logr.Logger) (string, error) {
+	if err := VerifySettings(UsernameSetting); err != nil {
 		logger.Info("Username cannot be emty")
-		panic(1)
+		return "", err
 	}
 
-	return user
+	user, _ := LookupSetting(UsernameSetting)
+	return user, nil
 }
 
-func GetPassword(logger logr.Logger) string {
-	base64Password, ok := os.LookupEnv(password)
-	if !ok {
-		logger.Info(fmt.Sprintf("Env variable %s supposed to contain password, base64 encoed, not found", password))
-		panic(1)
+// GetPassword returns the decoded password (see LookupSecret).
+// Returns an error matching ErrMissingConfig if not set.
+func GetPassword(logger logr.Logger) (string, error) {
+	if err := VerifySettings(PasswordSetting); err != nil {
+		logger.Info("Password cannot be emty")
+		return "", err
 	}
 
-	if base64Password == "" {
-		logger.Info("Password cannot be emty")
-		panic(1)
+	password, err := LookupSecret(PasswordSetting)
+	if err != nil {
+		logger.Info(fmt.Sprintf("Failed to get password: %v", err))
+		return "", err
 	}
+	return password, nil
+}
 
-	password, err := base64.StdEncoding.DecodeString(base64Password)
+// decodePassword returns the password base64 encoded in encodedPassword
+func decodePassword(encodedPassword string) (string, error) {
+	password, err := base64.StdEncoding.DecodeString(encodedPassword)
 	if err != nil {
-		logger.Info(fmt.Sprintf("Failed to decode password: %v", err))
-		panic(err)
+		return "", err
 	}
-	return string(password)
+	return string(password), nil
 }
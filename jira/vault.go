@@ -0,0 +1,175 @@
+package jira
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// jiraVaultPassphrase is the env variable with the vault passphrase, for non interactive use.
+	// When not set, the passphrase is asked on the terminal.
+	jiraVaultPassphrase = "JIRA_VAULT_PASSPHRASE"
+
+	// vaultVersion is the version of the vault file format
+	vaultVersion = 1
+	// vaultKDF is the key derivation function turning the passphrase into the encryption key
+	vaultKDF = "pbkdf2-sha256"
+	// vaultIterations is the number of PBKDF2 iterations used for new vaults
+	vaultIterations = 600000
+	// vaultMaxIterations bounds the iterations read from a vault file, so that a corrupted
+	// or tampered file cannot make key derivation run for hours
+	vaultMaxIterations = 10 * vaultIterations
+	// vaultKeyLength is the length of the AES-256 key
+	vaultKeyLength = 32
+	// vaultSaltLength is the length of the random PBKDF2 salt
+	vaultSaltLength = 16
+)
+
+// vaultFile is the content of a vault file: secrets are encrypted with AES-256-GCM,
+// using a key derived from the passphrase with PBKDF2-HMAC-SHA256
+type vaultFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// openVault decrypts the vault file content read from path with passphrase and returns its secrets
+func openVault(path string, content []byte, passphrase string) (map[string]string, error) {
+	vault := &vaultFile{}
+	if err := json.Unmarshal(content, vault); err != nil {
+		return nil, fmt.Errorf("failed to parse vault %s: %w", path, err)
+	}
+	if vault.Version != vaultVersion || vault.KDF != vaultKDF || vault.Iterations <= 0 {
+		return nil, fmt.Errorf("vault %s: unsupported format (version %d, kdf %s)", path, vault.Version, vault.KDF)
+	}
+	if vault.Iterations > vaultMaxIterations {
+		return nil, fmt.Errorf("vault %s: %d iterations exceed the maximum of %d: corrupted file", path,
+			vault.Iterations, vaultMaxIterations)
+	}
+
+	gcm, err := vaultCipher(passphrase, vault.Salt, vault.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(vault.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("vault %s: invalid nonce", path)
+	}
+	plaintext, err := gcm.Open(nil, vault.Nonce, vault.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock vault %s: wrong passphrase or corrupted file", path)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("vault %s: invalid content: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// sealVault returns the content of a vault file with secrets encrypted with passphrase.
+// Salt and nonce are new at every call.
+func sealVault(secrets map[string]string, passphrase string) ([]byte, error) {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	vault := &vaultFile{Version: vaultVersion, KDF: vaultKDF, Iterations: vaultIterations,
+		Salt: make([]byte, vaultSaltLength)}
+	if _, err := io.ReadFull(rand.Reader, vault.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := vaultCipher(passphrase, vault.Salt, vault.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	vault.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, vault.Nonce); err != nil {
+		return nil, err
+	}
+	vault.Data = gcm.Seal(nil, vault.Nonce, plaintext, nil)
+
+	return json.MarshalIndent(vault, "", "  ")
+}
+
+// vaultCipher returns the AES-256-GCM cipher keyed with passphrase
+func vaultCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, iterations, vaultKeyLength, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// vaultPassphrase returns the passphrase of vault path: the value of env variable
+// JIRA_VAULT_PASSPHRASE if set, asked on the terminal otherwise (twice if confirm is set)
+func vaultPassphrase(path string, confirm bool) (string, error) {
+	if passphrase := os.Getenv(jiraVaultPassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+
+	if !confirm {
+		return ReadSecret(fmt.Sprintf("Passphrase for vault %s: ", path))
+	}
+	passphrase, err := ReadSecret(fmt.Sprintf("New passphrase for vault %s: ", path))
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("vault passphrase cannot be empty")
+	}
+	again, err := ReadSecret("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// ReadSecret writes prompt and reads a line from the terminal without echoing it
+func ReadSecret(prompt string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if runtime.GOOS == "windows" || err != nil {
+		return "", fmt.Errorf("cannot read secret: no terminal available (set env variable %s for the vault passphrase)",
+			jiraVaultPassphrase)
+	}
+	defer tty.Close()
+
+	fmt.Fprint(tty, prompt)
+	if err := stty(tty, "-echo"); err == nil {
+		defer func() {
+			_ = stty(tty, "echo")
+			fmt.Fprintln(tty)
+		}()
+	}
+
+	line, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// stty changes the settings of terminal tty
+func stty(tty *os.File, setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = tty
+	return cmd.Run()
+}
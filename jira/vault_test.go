@@ -0,0 +1,103 @@
+package jira
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVaultRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets map[string]string
+	}{
+		{"empty", map[string]string{}},
+		{"single", map[string]string{PasswordSetting: "s3cret"}},
+		{"all", map[string]string{PasswordSetting: "p", TokenSetting: "t", OAuthClientSecretSetting: "näive ✓"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := sealVault(tt.secrets, "passphrase")
+			if err != nil {
+				t.Fatalf("sealVault: %v", err)
+			}
+			if strings.Contains(string(content), "s3cret") {
+				t.Fatalf("vault content contains a secret in clear: %s", content)
+			}
+
+			secrets, err := openVault("vault.json", content, "passphrase")
+			if err != nil {
+				t.Fatalf("openVault: %v", err)
+			}
+			if len(secrets) != len(tt.secrets) {
+				t.Fatalf("got %d secrets, want %d", len(secrets), len(tt.secrets))
+			}
+			for name, value := range tt.secrets {
+				if secrets[name] != value {
+					t.Errorf("secret %s: got %q, want %q", name, secrets[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestSealVaultIsSalted(t *testing.T) {
+	secrets := map[string]string{TokenSetting: "token"}
+	first, err := sealVault(secrets, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := sealVault(secrets, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) == string(second) {
+		t.Error("sealing the same secrets twice returned the same content")
+	}
+}
+
+func TestOpenVaultErrors(t *testing.T) {
+	content, err := sealVault(map[string]string{TokenSetting: "token"}, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamper := func(change func(*vaultFile)) []byte {
+		vault := &vaultFile{}
+		if err := json.Unmarshal(content, vault); err != nil {
+			t.Fatal(err)
+		}
+		change(vault)
+		tampered, err := json.Marshal(vault)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tampered
+	}
+
+	tests := []struct {
+		name       string
+		content    []byte
+		passphrase string
+		wantErr    string
+	}{
+		{"wrong passphrase", content, "wrong", "wrong passphrase or corrupted file"},
+		{"not json", []byte("secrets"), "passphrase", "failed to parse vault"},
+		{"unknown kdf", tamper(func(v *vaultFile) { v.KDF = "scrypt" }), "passphrase", "unsupported format"},
+		{"no iterations", tamper(func(v *vaultFile) { v.Iterations = 0 }), "passphrase", "unsupported format"},
+		{"too many iterations", tamper(func(v *vaultFile) { v.Iterations = vaultMaxIterations + 1 }), "passphrase",
+			"exceed the maximum"},
+		{"invalid nonce", tamper(func(v *vaultFile) { v.Nonce = v.Nonce[1:] }), "passphrase", "invalid nonce"},
+		{"corrupted data", tamper(func(v *vaultFile) { v.Data[0] ^= 0xff }), "passphrase",
+			"wrong passphrase or corrupted file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := openVault("vault.json", tt.content, tt.passphrase)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
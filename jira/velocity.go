@@ -0,0 +1,258 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/go-logr/logr"
+)
+
+const (
+	// rollingWindow is the number of sprints averaged by the rolling average of completed work
+	rollingWindow = 3
+	// barWidth is the width of the longest bar of ASCII bar charts
+	barWidth = 40
+)
+
+// Velocity is the document displayed by DisplayVelocity with json and yaml output.
+type Velocity struct {
+	Unit BurndownUnit `json:"unit" yaml:"unit"`
+	// Sprints are the closed sprints, oldest first
+	Sprints []SprintVelocity `json:"sprints" yaml:"sprints"`
+	// Average and StdDev are the mean and standard deviation of the work completed per sprint, in Unit
+	Average float64 `json:"average" yaml:"average"`
+	StdDev  float64 `json:"stdDev" yaml:"stdDev"`
+}
+
+// SprintVelocity is the work committed at the start of a sprint and completed by its end
+type SprintVelocity struct {
+	Sprint          string    `json:"sprint" yaml:"sprint"`
+	StartDate       time.Time `json:"startDate" yaml:"startDate"`
+	EndDate         time.Time `json:"endDate" yaml:"endDate"`
+	CommittedIssues int       `json:"committedIssues" yaml:"committedIssues"`
+	CommittedPoints float64   `json:"committedPoints" yaml:"committedPoints"`
+	CompletedIssues int       `json:"completedIssues" yaml:"completedIssues"`
+	CompletedPoints float64   `json:"completedPoints" yaml:"completedPoints"`
+	// RollingAverage is the mean work completed, in Unit, in this sprint and up to 2 previous ones
+	RollingAverage float64 `json:"rollingAverage" yaml:"rollingAverage"`
+}
+
+// committed and completed return the work of the sprint in unit
+func (v *SprintVelocity) committed(unit BurndownUnit) float64 {
+	if unit == UnitIssues {
+		return float64(v.CommittedIssues)
+	}
+	return v.CommittedPoints
+}
+
+func (v *SprintVelocity) completed(unit BurndownUnit) float64 {
+	if unit == UnitIssues {
+		return float64(v.CompletedIssues)
+	}
+	return v.CompletedPoints
+}
+
+// closedSprints returns the last n closed sprints (all if n is 0), oldest first
+func closedSprints(sprints []Sprint, n int) []jira.Sprint {
+	closed := make([]jira.Sprint, 0)
+	for i := range sprints {
+		if sprints[i].State == SprintClosed && sprints[i].StartDate != nil && sprints[i].EndDate != nil {
+			closed = append(closed, sprints[i].Sprint)
+		}
+	}
+	sort.SliceStable(closed, func(i, j int) bool {
+		_, endI, _ := sprintPeriod(&closed[i])
+		_, endJ, _ := sprintPeriod(&closed[j])
+		return endI.Before(endJ)
+	})
+	if n > 0 && len(closed) > n {
+		closed = closed[len(closed)-n:]
+	}
+	return closed
+}
+
+// GetVelocity returns, for the last n closed sprints of board boardID, the work committed at sprint
+// start and completed by sprint end, reconstructed from the issue changelogs (see GetBurndown).
+func GetVelocity(ctx context.Context, jiraClient JiraAPI, boardID string, n int, options *BurndownOptions,
+	logger logr.Logger) (*Velocity, error) {
+	velocityOptions := options.withDefaults()
+
+	boardSprints, err := GetJiraSprints(ctx, jiraClient, boardID, &SprintFilter{States: []string{SprintClosed}}, logger)
+	if err != nil {
+		return nil, err
+	}
+	sprints := closedSprints(boardSprints, n)
+	if len(sprints) == 0 {
+		return nil, fmt.Errorf("board %s has no closed sprint: %w", boardID, ErrNotFound)
+	}
+
+	// the project issues updated since the oldest sprint started are searched once for all sprints
+	search, err := newSprintIssueSearch(ctx, jiraClient, sprints, velocityOptions.Project, logger)
+	if err != nil {
+		return nil, err
+	}
+	velocity := &Velocity{Unit: velocityOptions.Unit, Sprints: make([]SprintVelocity, len(sprints))}
+	if velocity.Unit == UnitPoints && !search.hasPoints() {
+		logger.Info("Story points field not found: measuring work in issues")
+		velocity.Unit = UnitIssues
+	}
+	for i := range sprints {
+		issues, err := search.issues(ctx, &sprints[i])
+		if err != nil {
+			return nil, err
+		}
+
+		start, end, _ := sprintPeriod(&sprints[i])
+		committed := burndownDay(issues, sprints[i].Name, velocityOptions.DoneStatuses, start)
+		completed := burndownDay(issues, sprints[i].Name, velocityOptions.DoneStatuses, end)
+		velocity.Sprints[i] = SprintVelocity{Sprint: sprints[i].Name, StartDate: start, EndDate: end,
+			CommittedIssues: committed.ScopeIssues, CommittedPoints: committed.ScopePoints,
+			CompletedIssues: completed.DoneIssues, CompletedPoints: completed.DonePoints}
+	}
+
+	completed := make([]float64, len(velocity.Sprints))
+	for i := range velocity.Sprints {
+		completed[i] = velocity.Sprints[i].completed(velocity.Unit)
+		from := i - rollingWindow + 1
+		if from < 0 {
+			from = 0
+		}
+		velocity.Sprints[i].RollingAverage = roundDays(mean(completed[from : i+1]))
+	}
+	velocity.Average = roundDays(mean(completed))
+	velocity.StdDev = roundDays(stdDev(completed))
+	return velocity, nil
+}
+
+// DisplayVelocity displays work committed and completed in the last n closed sprints of board
+// boardID, with rolling average and standard deviation, as a table and an ASCII bar chart
+func DisplayVelocity(ctx context.Context, jiraClient JiraAPI, boardID string, n int,
+	velocityOptions *BurndownOptions, options *DisplayOptions, logger logr.Logger) error {
+	if options == nil {
+		options = &DisplayOptions{}
+	}
+
+	velocity, err := GetVelocity(ctx, jiraClient, boardID, n, velocityOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	if options.Template != nil {
+		return options.Template.Execute(options.writer(), velocity)
+	}
+	return writeVelocity(options.writer(), options.Output, velocity)
+}
+
+// mean returns the arithmetic mean of values, 0 if empty
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+// stdDev returns the population standard deviation of values, 0 if empty
+func stdDev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := mean(values)
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - m) * (v - m)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
+
+// velocityHeaders are the csv/tsv headers of a SprintVelocity
+var velocityHeaders = []string{"sprint", "startDate", "endDate", "committedIssues", "committedPoints",
+	"completedIssues", "completedPoints", "rollingAverage"}
+
+func (v *SprintVelocity) values() []string {
+	return []string{v.Sprint, formatTime(&v.StartDate), formatTime(&v.EndDate),
+		fmt.Sprintf("%d", v.CommittedIssues), formatNumber(v.CommittedPoints),
+		fmt.Sprintf("%d", v.CompletedIssues), formatNumber(v.CompletedPoints), formatNumber(v.RollingAverage)}
+}
+
+// writeVelocity writes velocity in the passed format
+func writeVelocity(w io.Writer, format OutputFormat, velocity *Velocity) error {
+	switch format {
+	case OutputJSON, OutputYAML:
+		return writeDocument(w, format, velocity)
+	case OutputCSV, OutputTSV:
+		rows := make([][]string, len(velocity.Sprints))
+		for i := range velocity.Sprints {
+			rows[i] = velocity.Sprints[i].values()
+		}
+		return writeSeparatedValues(w, format, velocityHeaders, rows)
+	}
+
+	unit := velocity.Unit
+	headers := []string{"SPRINT", "END", "COMMITTED", "COMPLETED", "COMPLETED %", "ROLLING AVG"}
+	rows := make([][]string, len(velocity.Sprints))
+	for i := range velocity.Sprints {
+		v := &velocity.Sprints[i]
+		ratio := ""
+		if v.committed(unit) > 0 {
+			ratio = fmt.Sprintf("%.0f%%", v.completed(unit)/v.committed(unit)*100)
+		}
+		rows[i] = []string{v.Sprint, formatDate(v.EndDate), formatNumber(v.committed(unit)),
+			formatNumber(v.completed(unit)), ratio, formatNumber(v.RollingAverage)}
+	}
+	caption := fmt.Sprintf("Velocity over %d sprints, in %s: average %s, standard deviation %s",
+		len(velocity.Sprints), unit, formatNumber(velocity.Average), formatNumber(velocity.StdDev))
+
+	if format == OutputMarkdown {
+		return writeMarkdown(w, headers, rows, caption)
+	}
+
+	if err := writeVelocityChart(w, velocity); err != nil {
+		return err
+	}
+	table := newTable(w, headers)
+	table.AppendBulk(rows)
+	table.Render()
+	_, err := fmt.Fprintln(w, caption)
+	return err
+}
+
+// writeVelocityChart writes an ASCII bar chart of velocity: for each sprint a bar
+// of = for committed work and a bar of # for completed work
+func writeVelocityChart(w io.Writer, velocity *Velocity) error {
+	unit := velocity.Unit
+	max := 0.0
+	nameWidth := 0
+	for i := range velocity.Sprints {
+		max = math.Max(max, math.Max(velocity.Sprints[i].committed(unit), velocity.Sprints[i].completed(unit)))
+		if len(velocity.Sprints[i].Sprint) > nameWidth {
+			nameWidth = len(velocity.Sprints[i].Sprint)
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+	bar := func(value float64, c string) string {
+		return strings.Repeat(c, int(math.Round(value/max*barWidth)))
+	}
+
+	var sb strings.Builder
+	for i := range velocity.Sprints {
+		v := &velocity.Sprints[i]
+		fmt.Fprintf(&sb, "%-*s |%s %s\n", nameWidth, v.Sprint, bar(v.committed(unit), "="), formatNumber(v.committed(unit)))
+		fmt.Fprintf(&sb, "%-*s |%s %s\n", nameWidth, "", bar(v.completed(unit), "#"), formatNumber(v.completed(unit)))
+	}
+	fmt.Fprintf(&sb, "%-*s  = committed %s  # completed %s\n\n", nameWidth, "", unit, unit)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,22 @@
+package jira
+
+import "testing"
+
+func TestStdDev(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   float64
+	}{
+		{nil, 0},
+		{[]float64{5}, 0},
+		{[]float64{3, 5}, 1},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 2},
+		{[]float64{1, 2, 3, 4}, 1.12},
+	}
+
+	for _, tt := range tests {
+		if got := roundDays(stdDev(tt.values)); got != tt.want {
+			t.Errorf("stdDev(%v) = %g, want %g", tt.values, got, tt.want)
+		}
+	}
+}
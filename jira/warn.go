@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+const (
+	// defaultWarnStatus is the status a threshold passed without status applies to
+	defaultWarnStatus = "In Progress"
+	// warnColumnName is the name of the column explaining why an issue is flagged
+	warnColumnName = "warn"
+)
+
+// WarnThresholds are how long issues can stay in a status before being flagged,
+// keyed by status name in lowercase
+type WarnThresholds map[string]time.Duration
+
+// ParseWarnThresholds parses a comma separated list of <status>=<age>, e.g.
+// "In Review=2,Blocked=5d,In Progress=36h". Age is a number of days, optionally
+// followed by d, or a duration (e.g. 36h). An age without status (e.g. "3")
+// applies to In Progress. Returns nil if value is empty.
+func ParseWarnThresholds(value string) (WarnThresholds, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	thresholds := WarnThresholds{}
+	for _, item := range strings.Split(value, ",") {
+		status, age := defaultWarnStatus, strings.TrimSpace(item)
+		if i := strings.LastIndex(item, "="); i >= 0 {
+			status, age = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		}
+		if status == "" {
+			return nil, fmt.Errorf("invalid warn threshold %q: status is missing", item)
+		}
+		threshold, err := parseAge(age)
+		if err != nil {
+			return nil, fmt.Errorf("invalid warn threshold %q: %w", item, err)
+		}
+		thresholds[strings.ToLower(status)] = threshold
+	}
+	return thresholds, nil
+}
+
+// parseAge parses a number of days (e.g. 2 or 2d) or a duration (e.g. 36h)
+func parseAge(age string) (time.Duration, error) {
+	if days, err := strconv.ParseFloat(strings.TrimSuffix(age, "d"), 64); err == nil && days > 0 {
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	if d, err := time.ParseDuration(age); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("age %q must be a number of days (e.g. 2 or 2d) or a duration (e.g. 36h)", age)
+}
+
+// applies returns true if issue current status has a threshold
+func (t WarnThresholds) applies(issue *jira.Issue) bool {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return false
+	}
+	_, ok := t[strings.ToLower(issue.Fields.Status.Name)]
+	return ok
+}
+
+// Reason returns why issue is flagged, e.g. "In Review for 3d, limit 2d": it has been in its current
+// status for longer than the status threshold at time now. Returns an empty string if not flagged.
+// Time in status comes from the issue changelog (see StatusSince), which must have been fetched:
+// issues without changelog (e.g. failed to fetch) or creation time are never flagged.
+func (t WarnThresholds) Reason(issue *jira.Issue, now time.Time) string {
+	if !t.applies(issue) || issue.Changelog == nil {
+		return ""
+	}
+	since := StatusSince(issue)
+	if since.IsZero() {
+		return ""
+	}
+	status := issue.Fields.Status.Name
+	threshold := t[strings.ToLower(status)]
+	inStatus := now.Sub(since)
+	if inStatus <= threshold {
+		return ""
+	}
+	return fmt.Sprintf("%s for %s, limit %s", status, formatAge(inStatus), formatAge(threshold))
+}
+
+// formatAge returns d in days (e.g. 3d), or hours if shorter than a day (e.g. 36h is 1.5d)
+func formatAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	days := d.Hours() / 24
+	if d%(24*time.Hour) == 0 || days >= 10 {
+		return fmt.Sprintf("%dd", int(days))
+	}
+	return fmt.Sprintf("%sd", strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", days), "0"), "."))
+}
+
+// warnColumn returns the column explaining why an issue is flagged with thresholds at time now
+func warnColumn(thresholds WarnThresholds, now time.Time) Column {
+	// status is always requested, created is needed when the changelog has no status transition
+	return Column{Name: warnColumnName, Header: "WARN", FieldID: "created",
+		value: func(i *jira.Issue) string { return thresholds.Reason(i, now) }}
+}
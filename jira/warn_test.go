@@ -0,0 +1,91 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// changelogTime is the format of changelog history dates
+const changelogTime = "2006-01-02T15:04:05.000-0700"
+
+// testIssue returns an issue in status, created at created, with a status transition at each of transitions
+func testIssue(status string, created time.Time, transitions ...time.Time) *jira.Issue {
+	issue := &jira.Issue{Key: "TEST-1", Fields: &jira.IssueFields{Status: &jira.Status{Name: status},
+		Created: jira.Time(created)}, Changelog: &jira.Changelog{}}
+	for _, at := range transitions {
+		issue.Changelog.Histories = append(issue.Changelog.Histories, jira.ChangelogHistory{
+			Created: at.Format(changelogTime),
+			Items:   []jira.ChangelogItems{{Field: statusField, ToString: status}},
+		})
+	}
+	return issue
+}
+
+func TestParseWarnThresholds(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    WarnThresholds
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "  ", want: nil},
+		{value: "3", want: WarnThresholds{"in progress": 3 * 24 * time.Hour}},
+		{value: "In Review=2,Blocked=5d,In Progress=36h", want: WarnThresholds{
+			"in review": 2 * 24 * time.Hour, "blocked": 5 * 24 * time.Hour, "in progress": 36 * time.Hour}},
+		{value: " In Review = 1.5 ", want: WarnThresholds{"in review": 36 * time.Hour}},
+		{value: "a=b=2", want: WarnThresholds{"a=b": 2 * 24 * time.Hour}},
+		{value: "=2", wantErr: true},
+		{value: "In Review=0", wantErr: true},
+		{value: "In Review=-1", wantErr: true},
+		{value: "In Review=soon", wantErr: true},
+		{value: "In Review=2,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseWarnThresholds(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWarnThresholds(%q): got error %v, want error %t", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseWarnThresholds(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWarnThresholdsReason(t *testing.T) {
+	now := time.Date(2022, 4, 29, 12, 0, 0, 0, time.UTC)
+	thresholds := WarnThresholds{"in review": 2 * 24 * time.Hour, "in progress": 36 * time.Hour}
+	withoutChangelog := testIssue("In Review", now.AddDate(0, 0, -30))
+	withoutChangelog.Changelog = nil
+
+	tests := []struct {
+		name  string
+		issue *jira.Issue
+		want  string
+	}{
+		{"status without threshold", testIssue("Backlog", now.AddDate(0, 0, -30)), ""},
+		{"within threshold", testIssue("In Review", now.AddDate(0, 0, -30), now.AddDate(0, 0, -1)), ""},
+		{"over threshold", testIssue("In Review", now.AddDate(0, 0, -30), now.AddDate(0, 0, -3)),
+			"In Review for 3d, limit 2d"},
+		{"status name is case insensitive", testIssue("IN PROGRESS", now.AddDate(0, 0, -30), now.Add(-48*time.Hour)),
+			"IN PROGRESS for 2d, limit 1.5d"},
+		{"latest transition is used", testIssue("In Review", now.AddDate(0, 0, -30), now.AddDate(0, 0, -10),
+			now.AddDate(0, 0, -1)), ""},
+		{"no transition: since creation", testIssue("In Review", now.AddDate(0, 0, -12)), "In Review for 12d, limit 2d"},
+		{"changelog not fetched", withoutChangelog, ""},
+		{"creation time not fetched", testIssue("In Review", time.Time{}), ""},
+		{"no fields", &jira.Issue{Key: "TEST-1"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := thresholds.Reason(tt.issue, now); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
@@ -16,25 +18,39 @@ import (
 )
 
 func main() {
-	ctx := context.Background()
+	// Ctrl-C cancels ctx, stopping requests in flight
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	klog.InitFlags(nil)
 	logger := klogr.New()
 
-	jira.VerifyEnvVariables(logger)
-
 	doc := `Usage:
 	jira_utils [options] <command> [<args>...]
 
 	show          Display information on jira issues
+	report        Display reports on sprints and issues
+	config        Display and validate jira_utils configuration
+	auth          Log in to Jira with OAuth and manage the cached token
+	secret        Manage secrets in the file or vault secret store
+	cache         Manage the cache of projects, boards and sprints
 
 Options:
-  -h --help     Show this screen.
-     --version  Show version.
+  -h --help            Show this screen.
+     --version         Show version.
+     --config=<file>   Config file (~/.config/jira_utils/config.yaml or value in env variable JIRA_UTILS_CONFIG by default).
+     --profile=<name>  Config file profile to use (value in env variable JIRA_PROFILE or config defaultProfile by default).
+     --no-cache        Fetch projects, boards and sprints from Jira, ignoring cached ones.
 
 Description:
   The jira-utils command line tool is used to manage/display jira issues.
   See 'jira-utils <command> --help' to read about a specific subcommand.
+  Settings (base URL, project, board, credentials) are taken from flags, then env variables,
+  then the selected config file profile.
+
+Exit codes:
+  0 success, 1 error, 2 missing configuration, 3 authentication failed, 4 not found
+  (project, board, sprint, issue), 5 ambiguous board, 6 no active sprint, 130 interrupted.
 `
 
 	parser := &docopt.Parser{
@@ -54,20 +70,63 @@ Description:
 		os.Exit(1)
 	}
 
+	configFile := ""
+	if passedConfig := opts["--config"]; passedConfig != nil {
+		configFile = passedConfig.(string)
+	}
+
+	profile := ""
+	if passedProfile := opts["--profile"]; passedProfile != nil {
+		profile = passedProfile.(string)
+	}
+
+	if opts["--no-cache"].(bool) {
+		jira.DisableCache()
+	}
+
+	config, err := jira.LoadConfig(configFile)
+	if err == nil {
+		err = jira.UseProfile(config, profile)
+	}
+	if err != nil {
+		logger.Info(fmt.Sprintf("Failed to load config. Error: %v", err))
+		exit(err)
+	}
+
 	if opts["<command>"] != nil {
 		command := opts["<command>"].(string)
 		args := append([]string{command}, opts["<args>"].([]string)...)
-		var err error
 
 		switch command {
 		case "show":
 			err = commands.Show(ctx, args)
+		case "report":
+			err = commands.Report(ctx, args)
+		case "config":
+			err = commands.Config(ctx, args)
+		case "auth":
+			err = commands.Auth(ctx, args)
+		case "secret":
+			err = commands.Secret(ctx, args)
+		case "cache":
+			err = commands.Cache(ctx, args)
 		default:
 			err = fmt.Errorf("unknown command: %q\n%s", command, doc)
 		}
 
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
+			exit(err)
 		}
 	}
 }
+
+// exit prints err, with a hint on how to fix it if any, and exits
+// with the exit code of the failure type (see commands.ExitCode)
+func exit(err error) {
+	code, hint := commands.ExitCode(err)
+	fmt.Fprintf(os.Stderr, "%v\n", err)
+	if hint != "" {
+		fmt.Fprintln(os.Stderr, hint)
+	}
+	os.Exit(code)
+}